@@ -3,14 +3,13 @@ package fahapi
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 	"net"
 	"net/url"
-	"regexp"
 	"strconv"
-	"strings"
 	"sync"
 )
 
@@ -24,7 +23,16 @@ var json = jsoniter.Config{}.Froze()
 type API struct {
 	*Connection
 	buffer *bytes.Buffer
-	mutex  sync.Mutex
+	mutex  *sync.Mutex // Guards buffer; shared by sessions unless the connection is pipelined
+	shared *apiState   // Shared by sessions
+}
+
+// apiState is the state of an API that is shared by its sessions.
+type apiState struct {
+	mutex          sync.Mutex
+	logSubscribers int // Number of SubscribeLog() channels
+	nextUpdateID   int
+	updatesResets  int // Number of UpdatesReset() calls, which let watchers skip throttling
 }
 
 // DefaultAddr is the default TCP address of the FAH client.
@@ -32,20 +40,59 @@ var DefaultAddr = &net.TCPAddr{Port: 36330}
 
 // Dial connects to your FAH client. DefaultAddr is the default client address.
 func Dial(addr *net.TCPAddr) (*API, error) {
-	conn, err := DialConnection(addr)
+	return DialContext(context.Background(), addr.String())
+}
+
+// DialContext connects to the FAH client at addr, which can be a hostname like
+// "example.com:36330". ctx is only used while connecting. See DialOption for the options.
+func DialContext(ctx context.Context, addr string, options ...DialOption) (*API, error) {
+	conn, err := DialConnectionContext(ctx, addr, options...)
 	if err != nil {
 		return nil, err
 	}
 
-	return &API{Connection: conn, buffer: &bytes.Buffer{}}, nil
+	return &API{
+		Connection: conn,
+		buffer:     &bytes.Buffer{},
+		mutex:      &sync.Mutex{},
+		shared:     &apiState{},
+	}, nil
+}
+
+// Auth authenticates with the client's password. Clients with a password reject commands until
+// authenticated. The password is sent again when the connection is reestablished.
+func (a *API) Auth(password string) error {
+	return a.AuthContext(context.Background(), password)
+}
+
+// AuthContext is Auth with a context.
+func (a *API) AuthContext(ctx context.Context, password string) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if err := a.ExecContext(ctx, authCommand(password), a.buffer); err != nil {
+		return err
+	}
+
+	if err := checkAuthResponse(a.buffer.String()); err != nil {
+		return err
+	}
+
+	a.password = password
+	return nil
 }
 
 // Help returns a listing of the FAH API commands.
 func (a *API) Help() (string, error) {
+	return a.HelpContext(context.Background())
+}
+
+// HelpContext is Help with a context.
+func (a *API) HelpContext(ctx context.Context) (string, error) {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	err := a.Exec("help", a.buffer)
+	err := a.ExecContext(ctx, "help", a.buffer)
 	if err != nil {
 		return "", err
 	}
@@ -61,91 +108,109 @@ const (
 	LogUpdatesStop    = LogUpdatesArg("stop")
 )
 
-// LogUpdates enables or disables log updates. Returns current log.
+// LogUpdates enables or disables log updates. Returns current log for LogUpdatesStart and
+// LogUpdatesRestart. Use SubscribeLog() to receive the updates. LogUpdatesStop returns an error
+// while a channel from SubscribeLog() is open, because it would stop the updates of the channel.
 func (a *API) LogUpdates(arg LogUpdatesArg) (string, error) {
+	return a.LogUpdatesContext(context.Background(), arg)
+}
+
+// LogUpdatesContext is LogUpdates with a context.
+func (a *API) LogUpdatesContext(ctx context.Context, arg LogUpdatesArg) (string, error) {
 	/*
 		This command is weird. It returns the log after the next prompt, like this:
 		> log-updates start
 
 		>
 		PyON 1 log-update...
+
+		The reader goroutine separates the log-update message from responses.
 	*/
-	a.mutex.Lock()
-	defer a.mutex.Unlock()
+	if arg == LogUpdatesStop {
+		return "", a.stopLogUpdates(ctx)
+	}
 
-	if err := a.Exec(fmt.Sprintf("log-updates %s", arg), a.buffer); err != nil {
+	s := a.subscribe(logUpdateName)
+	defer a.unsubscribe(s)
+
+	if err := a.logUpdates(ctx, arg); err != nil {
 		return "", err
 	}
 
-	if err := a.ExecEval("eval", a.buffer); err != nil {
-		return "", nil
+	message, err := s.next(ctx)
+	if err != nil {
+		return "", err
 	}
 
-	// The string contains a bunch of \x00 sequences that are not valid JSON and cannot be
-	// unmarshalled using UnmarshalPyON().
-	return parseLog(a.buffer.Bytes())
+	return parseLog(message)
 }
 
-func parseLog(b []byte) (string, error) {
-	// The log looks like this: PyON 1 log-update\n"..."\n---\n\n
-	const suffix = "\n---\n\n"
+// stopLogUpdates disables log updates unless SubscribeLog() uses them.
+func (a *API) stopLogUpdates(ctx context.Context) error {
+	a.shared.mutex.Lock()
+	defer a.shared.mutex.Unlock()
 
-	removedSuffix := bytes.TrimSuffix(b, []byte(suffix))
-	removedPrefix := removedSuffix[bytes.IndexByte(removedSuffix, '\n')+1:]
-	return ParsePyONString(removedPrefix)
-}
+	if a.shared.logSubscribers > 0 {
+		return errors.Errorf("log updates are used by %d subscribers", a.shared.logSubscribers)
+	}
 
-var matchEscaped = regexp.MustCompile(`\\x..|\\n|\\r|\\"|\\\\`)
+	return a.logUpdates(ctx, LogUpdatesStop)
+}
 
-func ParsePyONString(b []byte) (string, error) {
-	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
-		return "", errors.New("b is not a valid PyON string")
-	}
+func (a *API) logUpdates(ctx context.Context, arg LogUpdatesArg) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
 
-	replaceFunc := func(b []byte) []byte {
-		if bytes.Equal(b, []byte(`\n`)) {
-			return []byte("\n")
-		} else if bytes.Equal(b, []byte(`\r`)) {
-			return []byte("\r")
-		} else if bytes.Equal(b, []byte(`\"`)) {
-			return []byte(`"`)
-		} else if bytes.Equal(b, []byte(`\\`)) {
-			return []byte(`\`)
-		}
+	return a.ExecContext(ctx, fmt.Sprintf("log-updates %s", arg), a.buffer)
+}
 
-		n, err := strconv.ParseInt(string(b[2:]), 16, 32)
-		if err != nil {
-			return b
-		}
+func parseLog(b []byte) (string, error) {
+	// The log looks like this: PyON 1 log-update\n"..."\n---\n
+	const suffix = "\n---"
 
-		return []byte(string(rune(n)))
-	}
-	return string(matchEscaped.ReplaceAllFunc(b[1:len(b)-1], replaceFunc)), nil
+	removedSuffix := bytes.TrimSuffix(bytes.TrimRight(b, "\n"), []byte(suffix))
+	removedPrefix := removedSuffix[bytes.IndexByte(removedSuffix, '\n')+1:]
+	return ParsePyONString(removedPrefix)
 }
 
 // Screensaver unpauses all slots which are paused waiting for a screensaver and pause them again on
 // disconnect.
 func (a *API) Screensaver() error {
+	return a.ScreensaverContext(context.Background())
+}
+
+// ScreensaverContext is Screensaver with a context.
+func (a *API) ScreensaverContext(ctx context.Context) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	return a.Exec("screensaver", a.buffer)
+	return a.ExecContext(ctx, "screensaver", a.buffer)
 }
 
 // AlwaysOn sets a slot to be always on. (Not sure if this does anything at all.)
 func (a *API) AlwaysOn(slot int) error {
+	return a.AlwaysOnContext(context.Background(), slot)
+}
+
+// AlwaysOnContext is AlwaysOn with a context.
+func (a *API) AlwaysOnContext(ctx context.Context, slot int) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	return a.Exec(fmt.Sprintf("always_on %d", slot), a.buffer)
+	return a.ExecContext(ctx, fmt.Sprintf("always_on %d", slot), a.buffer)
 }
 
 // Configured returns true if the client has set a user, team or passkey.
 func (a *API) Configured() (bool, error) {
+	return a.ConfiguredContext(context.Background())
+}
+
+// ConfiguredContext is Configured with a context.
+func (a *API) ConfiguredContext(ctx context.Context) (bool, error) {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	if err := a.Exec("configured", a.buffer); err != nil {
+	if err := a.ExecContext(ctx, "configured", a.buffer); err != nil {
 		return false, err
 	}
 
@@ -158,26 +223,41 @@ func (a *API) Configured() (bool, error) {
 
 // DoCycle runs one client cycle.
 func (a *API) DoCycle() error {
+	return a.DoCycleContext(context.Background())
+}
+
+// DoCycleContext is DoCycle with a context.
+func (a *API) DoCycleContext(ctx context.Context) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	return a.Exec("do-cycle", a.buffer)
+	return a.ExecContext(ctx, "do-cycle", a.buffer)
 }
 
 // DownloadCore downloads a core. NOT TESTED.
 func (a *API) DownloadCore(coreType string, url *url.URL) error {
+	return a.DownloadCoreContext(context.Background(), coreType, url)
+}
+
+// DownloadCoreContext is DownloadCore with a context.
+func (a *API) DownloadCoreContext(ctx context.Context, coreType string, url *url.URL) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	return a.Exec(fmt.Sprintf("download-core %s %s", coreType, url.String()), a.buffer)
+	return a.ExecContext(ctx, fmt.Sprintf("download-core %s %s", coreType, url.String()), a.buffer)
 }
 
 // FinishSlot pauses a slot when its current work unit is completed.
 func (a *API) FinishSlot(slot int) error {
+	return a.FinishSlotContext(context.Background(), slot)
+}
+
+// FinishSlotContext is FinishSlot with a context.
+func (a *API) FinishSlotContext(ctx context.Context, slot int) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	return a.Exec(fmt.Sprintf("finish %d", slot), a.buffer)
+	return a.ExecContext(ctx, fmt.Sprintf("finish %d", slot), a.buffer)
 }
 
 // Finish pauses a slot when its current work unit is completed.
@@ -191,18 +271,28 @@ func (a *API) Finish(slot int) error {
 
 // FinishAll pauses all slots one-by-one when their current work unit is completed.
 func (a *API) FinishAll() error {
+	return a.FinishAllContext(context.Background())
+}
+
+// FinishAllContext is FinishAll with a context.
+func (a *API) FinishAllContext(ctx context.Context) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	return a.Exec("finish", a.buffer)
+	return a.ExecContext(ctx, "finish", a.buffer)
 }
 
 // Info returns FAH build and machine info. See InfoStruct().
 func (a *API) Info() ([][]interface{}, error) {
+	return a.InfoContext(context.Background())
+}
+
+// InfoContext is Info with a context.
+func (a *API) InfoContext(ctx context.Context) ([][]interface{}, error) {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	if err := a.Exec("info", a.buffer); err != nil {
+	if err := a.ExecContext(ctx, "info", a.buffer); err != nil {
 		return nil, err
 	}
 
@@ -212,7 +302,12 @@ func (a *API) Info() ([][]interface{}, error) {
 
 // InfoStruct converts Info() data into a structure. Consider this interface to be very unstable.
 func (a *API) InfoStruct(dst *Info) error {
-	src, err := a.Info()
+	return a.InfoStructContext(context.Background(), dst)
+}
+
+// InfoStructContext is InfoStruct with a context.
+func (a *API) InfoStructContext(ctx context.Context, dst *Info) error {
+	src, err := a.InfoContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -222,10 +317,15 @@ func (a *API) InfoStruct(dst *Info) error {
 
 // NumSlots returns the number of slots.
 func (a *API) NumSlots() (int, error) {
+	return a.NumSlotsContext(context.Background())
+}
+
+// NumSlotsContext is NumSlots with a context.
+func (a *API) NumSlotsContext(ctx context.Context) (int, error) {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	if err := a.Exec("num-slots", a.buffer); err != nil {
+	if err := a.ExecContext(ctx, "num-slots", a.buffer); err != nil {
 		return 0, err
 	}
 
@@ -235,69 +335,195 @@ func (a *API) NumSlots() (int, error) {
 
 // OnIdle sets a slot to run only when idle.
 func (a *API) OnIdle(slot int) error {
+	return a.OnIdleContext(context.Background(), slot)
+}
+
+// OnIdleContext is OnIdle with a context.
+func (a *API) OnIdleContext(ctx context.Context, slot int) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	return a.Exec(fmt.Sprintf("on_idle %d", slot), a.buffer)
+	return a.ExecContext(ctx, fmt.Sprintf("on_idle %d", slot), a.buffer)
 }
 
 // OnIdle sets all slots to run only when idle.
 func (a *API) OnIdleAll() error {
+	return a.OnIdleAllContext(context.Background())
+}
+
+// OnIdleAllContext is OnIdleAll with a context.
+func (a *API) OnIdleAllContext(ctx context.Context) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	return a.Exec("on_idle", a.buffer)
+	return a.ExecContext(ctx, "on_idle", a.buffer)
 }
 
 // OptionsGet returns the FAH client options.
 func (a *API) OptionsGet(dst *Options) error {
+	return a.OptionsGetContext(context.Background(), dst)
+}
+
+// OptionsGetContext is OptionsGet with a context.
+func (a *API) OptionsGetContext(ctx context.Context, dst *Options) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	if err := a.Exec("options -a", a.buffer); err != nil {
+	if err := a.ExecContext(ctx, "options -a", a.buffer); err != nil {
 		return err
 	}
 
 	return UnmarshalPyON(a.buffer.Bytes(), &dst)
 }
 
-// OptionsSet sets an option. value argument is turned into a string using fmt.Sprintf().
+// OptionsSet sets an option. value argument is turned into a string using fmt.Sprintf(), and is
+// checked against the type of the option in Options.
 func (a *API) OptionsSet(key string, value interface{}) error {
+	return a.OptionsSetContext(context.Background(), key, value)
+}
+
+// OptionsSetContext is OptionsSet with a context.
+func (a *API) OptionsSetContext(ctx context.Context, key string, value interface{}) error {
+	if value == nil {
+		return errors.Errorf("no value for option %s", key)
+	}
+
+	return a.OptionsUpdateContext(ctx, OptionChanges{key: value}, nil)
+}
+
+// OptionsChanged returns options that have non-default values.
+func (a *API) OptionsChanged() (map[string]string, error) {
+	return a.OptionsChangedContext(context.Background())
+}
+
+// OptionsChangedContext is OptionsChanged with a context.
+func (a *API) OptionsChangedContext(ctx context.Context) (map[string]string, error) {
+	return a.optionsList(ctx, "options")
+}
+
+// OptionsWithDefaults returns options that have values, including default values. Unlike
+// OptionsGet, unset options are not included.
+func (a *API) OptionsWithDefaults() (map[string]string, error) {
+	return a.OptionsWithDefaultsContext(context.Background())
+}
+
+// OptionsWithDefaultsContext is OptionsWithDefaults with a context.
+func (a *API) OptionsWithDefaultsContext(ctx context.Context) (map[string]string, error) {
+	return a.optionsList(ctx, "options -d")
+}
+
+func (a *API) optionsList(ctx context.Context, command string) (map[string]string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if err := a.ExecContext(ctx, command, a.buffer); err != nil {
+		return nil, err
+	}
+
+	result := map[string]string{}
+	return result, UnmarshalPyON(a.buffer.Bytes(), &result)
+}
+
+// OptionsApply sets the non-nil fields of patch in one command. See OptionsUpdate.
+func (a *API) OptionsApply(patch *OptionsPatch, dst *Options) error {
+	return a.OptionsApplyContext(context.Background(), patch, dst)
+}
+
+// OptionsApplyContext is OptionsApply with a context.
+func (a *API) OptionsApplyContext(ctx context.Context, patch *OptionsPatch, dst *Options) error {
+	return a.OptionsUpdateContext(ctx, patch.changes(), dst)
+}
+
+// OptionsReset resets options to their defaults. The new values are stored in dst, which can be
+// nil.
+func (a *API) OptionsReset(dst *Options, keys ...string) error {
+	return a.OptionsResetContext(context.Background(), dst, keys...)
+}
+
+// OptionsResetContext is OptionsReset with a context.
+func (a *API) OptionsResetContext(ctx context.Context, dst *Options, keys ...string) error {
+	changes := make(OptionChanges, len(keys))
+	for _, key := range keys {
+		changes[key] = nil
+	}
+	return a.OptionsUpdateContext(ctx, changes, dst)
+}
+
+// OptionsUpdate sets and resets multiple options in one command. Options with nil values are reset
+// to their defaults. Unknown options and invalid values are rejected before the command is sent.
+// The values that the client stored for the changed options are stored in dst; other fields of dst
+// are not modified. dst can be nil.
+func (a *API) OptionsUpdate(changes OptionChanges, dst *Options) error {
+	return a.OptionsUpdateContext(context.Background(), changes, dst)
+}
+
+// OptionsUpdateContext is OptionsUpdate with a context.
+func (a *API) OptionsUpdateContext(ctx context.Context, changes OptionChanges, dst *Options) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	changes, err := changes.validate()
+	if err != nil {
+		return err
+	}
+
+	args, err := changes.format("=")
+	if err != nil {
+		return err
+	}
+
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	// Prevent injection attacks
-	valueString := fmt.Sprintf("%v", value)
-	if strings.ContainsAny(key, "= !") || strings.ContainsRune(valueString, ' ') {
-		return errors.New("key or value contains bad char")
+	if err := a.ExecContext(ctx, "options"+args, a.buffer); err != nil {
+		return err
 	}
 
-	return a.Exec(fmt.Sprintf("options %s=%s", key, valueString), a.buffer)
+	if dst == nil {
+		return nil
+	}
+	return UnmarshalPyON(a.buffer.Bytes(), dst)
 }
 
 // PauseAll pauses all slots.
 func (a *API) PauseAll() error {
+	return a.PauseAllContext(context.Background())
+}
+
+// PauseAllContext is PauseAll with a context.
+func (a *API) PauseAllContext(ctx context.Context) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	return a.Exec("pause", a.buffer)
+	return a.ExecContext(ctx, "pause", a.buffer)
 }
 
 // PauseSlot pauses a slot.
 func (a *API) PauseSlot(slot int) error {
+	return a.PauseSlotContext(context.Background(), slot)
+}
+
+// PauseSlotContext is PauseSlot with a context.
+func (a *API) PauseSlotContext(ctx context.Context, slot int) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	// Unfortunately the command doesn't tell you if it succeeded or not
-	return a.Exec(fmt.Sprintf("pause %d", slot), a.buffer)
+	// Some clients do not respond with an error for an invalid slot
+	return a.ExecContext(ctx, fmt.Sprintf("pause %d", slot), a.buffer)
 }
 
 // PPD returns the total estimated points per day for all slots.
 func (a *API) PPD() (float64, error) {
+	return a.PPDContext(context.Background())
+}
+
+// PPDContext is PPD with a context.
+func (a *API) PPDContext(ctx context.Context) (float64, error) {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	if err := a.Exec("ppd", a.buffer); err != nil {
+	if err := a.ExecContext(ctx, "ppd", a.buffer); err != nil {
 		return 0, err
 	}
 	result := 0.0
@@ -306,10 +532,15 @@ func (a *API) PPD() (float64, error) {
 
 // QueueInfo returns info about the current work unit.
 func (a *API) QueueInfo() ([]SlotQueueInfo, error) {
+	return a.QueueInfoContext(context.Background())
+}
+
+// QueueInfoContext is QueueInfo with a context.
+func (a *API) QueueInfoContext(ctx context.Context) ([]SlotQueueInfo, error) {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	if err := a.Exec("queue-info", a.buffer); err != nil {
+	if err := a.ExecContext(ctx, "queue-info", a.buffer); err != nil {
 		return nil, err
 	}
 
@@ -323,35 +554,74 @@ func (a *API) QueueInfo() ([]SlotQueueInfo, error) {
 
 // RequestID requests an ID from the assignment server.
 func (a *API) RequestID() error {
+	return a.RequestIDContext(context.Background())
+}
+
+// RequestIDContext is RequestID with a context.
+func (a *API) RequestIDContext(ctx context.Context) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	return a.Exec("request-id", a.buffer)
+	return a.ExecContext(ctx, "request-id", a.buffer)
 }
 
 // RequestWS requests work server assignment from the assignment server.
 func (a *API) RequestWS() error {
+	return a.RequestWSContext(context.Background())
+}
+
+// RequestWSContext is RequestWS with a context.
+func (a *API) RequestWSContext(ctx context.Context) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.ExecContext(ctx, "request-ws", a.buffer)
+}
+
+// Save writes the configuration to path, or to the file that the configuration was loaded from if
+// path is empty. A relative path is relative to the working directory of the client.
+func (a *API) Save(path string) error {
+	return a.SaveContext(context.Background(), path)
+}
+
+// SaveContext is Save with a context.
+func (a *API) SaveContext(ctx context.Context, path string) error {
+	command := "save"
+	if path != "" {
+		command += " " + quoteArg(path)
+	}
+
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	return a.Exec("request-ws", a.buffer)
+	return a.ExecContext(ctx, command, a.buffer)
 }
 
 // Shutdown ends all FAH processes.
 func (a *API) Shutdown() error {
+	return a.ShutdownContext(context.Background())
+}
+
+// ShutdownContext is Shutdown with a context.
+func (a *API) ShutdownContext(ctx context.Context) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	return a.Exec("shutdown", a.buffer)
+	return a.ExecContext(ctx, "shutdown", a.buffer)
 }
 
 // SimulationInfo returns the simulation information for a slot.
 func (a *API) SimulationInfo(slot int, dst *SimulationInfo) error {
+	return a.SimulationInfoContext(context.Background(), slot, dst)
+}
+
+// SimulationInfoContext is SimulationInfo with a context.
+func (a *API) SimulationInfoContext(ctx context.Context, slot int, dst *SimulationInfo) error {
 	// "just like the simulations"
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	if err := a.Exec(fmt.Sprintf("simulation-info %d", slot), a.buffer); err != nil {
+	if err := a.ExecContext(ctx, fmt.Sprintf("simulation-info %d", slot), a.buffer); err != nil {
 		return err
 	}
 
@@ -361,20 +631,105 @@ func (a *API) SimulationInfo(slot int, dst *SimulationInfo) error {
 	return nil
 }
 
+// SlotAdd adds a slot and returns its ID. slot-add only sets options, so changes cannot contain
+// nil values.
+func (a *API) SlotAdd(slotType SlotType, changes SlotChanges) (int, error) {
+	return a.SlotAddContext(context.Background(), slotType, changes)
+}
+
+// SlotAddContext is SlotAdd with a context.
+func (a *API) SlotAddContext(
+	ctx context.Context,
+	slotType SlotType,
+	changes SlotChanges,
+) (int, error) {
+	for name, value := range changes {
+		if value == nil {
+			return 0, errors.Errorf("slot-add cannot reset %s", name)
+		}
+	}
+
+	changes, err := changes.validate()
+	if err != nil {
+		return 0, err
+	}
+
+	args, err := changes.args()
+	if err != nil {
+		return 0, err
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	before, err := a.slotIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := a.ExecContext(ctx, "slot-add "+string(slotType)+args, a.buffer); err != nil {
+		return 0, err
+	}
+
+	after, err := a.slotIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			return id, nil
+		}
+	}
+	return 0, errors.New("slot was not added")
+}
+
+// slotIDs returns the set of slot IDs. It must be called with a.mutex held.
+func (a *API) slotIDs(ctx context.Context) (map[int]struct{}, error) {
+	if err := a.ExecContext(ctx, "slot-info", a.buffer); err != nil {
+		return nil, err
+	}
+
+	var slots []SlotInfo
+	if err := UnmarshalPyON(a.buffer.Bytes(), &slots); err != nil {
+		return nil, err
+	}
+
+	result := make(map[int]struct{}, len(slots))
+	for _, slot := range slots {
+		id, err := strconv.Atoi(slot.ID)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		result[id] = struct{}{}
+	}
+	return result, nil
+}
+
 // SlotDelete deletes a slot.
 func (a *API) SlotDelete(slot int) error {
+	return a.SlotDeleteContext(context.Background(), slot)
+}
+
+// SlotDeleteContext is SlotDelete with a context.
+func (a *API) SlotDeleteContext(ctx context.Context, slot int) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	return a.Exec(fmt.Sprintf("slot-delete %d", slot), a.buffer)
+	return a.ExecContext(ctx, fmt.Sprintf("slot-delete %d", slot), a.buffer)
 }
 
 // SlotInfo returns information about each slot.
 func (a *API) SlotInfo() ([]SlotInfo, error) {
+	return a.SlotInfoContext(context.Background())
+}
+
+// SlotInfoContext is SlotInfo with a context.
+func (a *API) SlotInfoContext(ctx context.Context) ([]SlotInfo, error) {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	if err := a.Exec("slot-info", a.buffer); err != nil {
+	if err := a.ExecContext(ctx, "slot-info", a.buffer); err != nil {
 		return nil, err
 	}
 
@@ -382,46 +737,151 @@ func (a *API) SlotInfo() ([]SlotInfo, error) {
 	return result, UnmarshalPyON(a.buffer.Bytes(), &result)
 }
 
+// SlotModify changes the type and options of a slot. Options with nil values are reset to their
+// defaults.
+func (a *API) SlotModify(slot int, slotType SlotType, changes SlotChanges) error {
+	return a.SlotModifyContext(context.Background(), slot, slotType, changes)
+}
+
+// SlotModifyContext is SlotModify with a context.
+func (a *API) SlotModifyContext(
+	ctx context.Context,
+	slot int,
+	slotType SlotType,
+	changes SlotChanges,
+) error {
+	changes, err := changes.validate()
+	if err != nil {
+		return err
+	}
+
+	args, err := changes.args()
+	if err != nil {
+		return err
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	command := fmt.Sprintf("slot-modify %d %s%s", slot, slotType, args)
+	return a.ExecContext(ctx, command, a.buffer)
+}
+
+// SlotOptionsGet gets all options of a slot.
 func (a *API) SlotOptionsGet(slot int, dst *SlotOptions) error {
+	return a.SlotOptionsGetContext(context.Background(), slot, dst)
+}
+
+// SlotOptionsGetContext is SlotOptionsGet with a context.
+func (a *API) SlotOptionsGetContext(ctx context.Context, slot int, dst *SlotOptions) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	if err := a.Exec(fmt.Sprintf("slot-options %d -a", slot), a.buffer); err != nil {
+	if err := a.ExecContext(ctx, fmt.Sprintf("slot-options %d -a", slot), a.buffer); err != nil {
 		return err
 	}
 
 	return UnmarshalPyON(a.buffer.Bytes(), dst)
 }
 
+// SlotOptionsSet sets a slot option. value argument is turned into a string using fmt.Sprintf().
 func (a *API) SlotOptionsSet(slot int, key string, value interface{}) error {
+	return a.SlotOptionsSetContext(context.Background(), slot, key, value)
+}
+
+// SlotOptionsSetContext is SlotOptionsSet with a context.
+func (a *API) SlotOptionsSetContext(
+	ctx context.Context,
+	slot int,
+	key string,
+	value interface{},
+) error {
+	if value == nil {
+		return errors.Errorf("no value for slot option %s", key)
+	}
+
+	return a.SlotOptionsUpdateContext(ctx, slot, SlotChanges{key: value})
+}
+
+// SlotOptionsUpdate sets and resets multiple slot options in one command. Options with nil values
+// are reset to their defaults.
+func (a *API) SlotOptionsUpdate(slot int, changes SlotChanges) error {
+	return a.SlotOptionsUpdateContext(context.Background(), slot, changes)
+}
+
+// SlotOptionsUpdateContext is SlotOptionsUpdate with a context.
+func (a *API) SlotOptionsUpdateContext(ctx context.Context, slot int, changes SlotChanges) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	changes, err := changes.validate()
+	if err != nil {
+		return err
+	}
+
+	args, err := changes.format(" ")
+	if err != nil {
+		return err
+	}
+
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	return a.Exec(fmt.Sprintf("slot-options %d %s %v", slot, key, value), a.buffer)
+	return a.ExecContext(ctx, fmt.Sprintf("slot-options %d%s", slot, args), a.buffer)
+}
+
+// SlotOptionsReset resets slot options to their defaults.
+func (a *API) SlotOptionsReset(slot int, keys ...string) error {
+	return a.SlotOptionsResetContext(context.Background(), slot, keys...)
+}
+
+// SlotOptionsResetContext is SlotOptionsReset with a context.
+func (a *API) SlotOptionsResetContext(ctx context.Context, slot int, keys ...string) error {
+	changes := make(SlotChanges, len(keys))
+	for _, key := range keys {
+		changes[key] = nil
+	}
+	return a.SlotOptionsUpdateContext(ctx, slot, changes)
 }
 
 // UnpauseAll unpauses all slots.
 func (a *API) UnpauseAll() error {
+	return a.UnpauseAllContext(context.Background())
+}
+
+// UnpauseAllContext is UnpauseAll with a context.
+func (a *API) UnpauseAllContext(ctx context.Context) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	return a.Exec("unpause", a.buffer)
+	return a.ExecContext(ctx, "unpause", a.buffer)
 }
 
 // UnpauseSlot unpauses a slot.
 func (a *API) UnpauseSlot(slot int) error {
+	return a.UnpauseSlotContext(context.Background(), slot)
+}
+
+// UnpauseSlotContext is UnpauseSlot with a context.
+func (a *API) UnpauseSlotContext(ctx context.Context, slot int) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	return a.Exec(fmt.Sprintf("unpause %d", slot), a.buffer)
+	return a.ExecContext(ctx, fmt.Sprintf("unpause %d", slot), a.buffer)
 }
 
 // Uptime returns FAH uptime.
 func (a *API) Uptime() (FAHDuration, error) {
+	return a.UptimeContext(context.Background())
+}
+
+// UptimeContext is Uptime with a context.
+func (a *API) UptimeContext(ctx context.Context) (FAHDuration, error) {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	if err := a.ExecEval("uptime", a.buffer); err != nil {
+	if err := a.ExecEvalContext(ctx, "uptime", a.buffer); err != nil {
 		return 0, err
 	}
 
@@ -430,58 +890,13 @@ func (a *API) Uptime() (FAHDuration, error) {
 
 // WaitForUnits blocks until all slots are paused.
 func (a *API) WaitForUnits() error {
-	a.mutex.Lock()
-	defer a.mutex.Unlock()
-
-	return a.Exec("wait-for-units", a.buffer)
+	return a.WaitForUnitsContext(context.Background())
 }
 
-func UnmarshalPyON(b []byte, dst interface{}) error {
-	// https://pypi.org/project/pon/
-	if !bytes.HasPrefix(b, []byte("PyON")) || !bytes.HasSuffix(b, []byte("\n---")) {
-		return errors.Errorf("invalid PyON format: %s", b)
-	}
-
-	start := bytes.IndexByte(b, '\n') + 1
-	end := len(b) - len("\n---")
-	if start > end {
-		start = end
-	}
-
-	var replaced []byte
-	if bytes.Equal(b[start:end], []byte("True")) {
-		replaced = []byte("true")
-	} else if bytes.Equal(b[start:end], []byte("False")) {
-		replaced = []byte("false")
-	} else {
-		replaced = bytes.ReplaceAll(b[start:end], []byte(": None"), []byte(`: ""`))
-		replace(replaced, []byte(": False"), []byte(": false"))
-		replace(replaced, []byte(": True"), []byte(": true"))
-	}
-	return errors.WithStack(json.Unmarshal(replaced, dst))
-}
-
-func replace(b []byte, old []byte, new []byte) {
-	if len(old) != len(new) {
-		panic("old and new must have the same length")
-	}
-
-	i := 0
-	for {
-		if i > len(b) {
-			return
-		}
-
-		if sliceIndex := bytes.Index(b[i:], old); sliceIndex >= 0 {
-			i = sliceIndex + i
-		} else {
-			return
-		}
+// WaitForUnitsContext is WaitForUnits with a context.
+func (a *API) WaitForUnitsContext(ctx context.Context) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
 
-		if i > i+len(old) || i+len(old) > len(b) {
-			return
-		}
-		copy(b[i:i+len(new)], new)
-		i++
-	}
+	return a.ExecContext(ctx, "wait-for-units", a.buffer)
 }
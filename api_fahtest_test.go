@@ -0,0 +1,966 @@
+package fahapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/MakotoE/go-fahapi"
+	"github.com/MakotoE/go-fahapi/fahtest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// These tests run every API method against fahtest.Server instead of a real client.
+
+func dialFake(t *testing.T) (*fahtest.Server, *fahapi.API) {
+	server := fahtest.NewServer()
+	api, err := fahapi.DialContext(context.Background(), server.Addr())
+	require.Nil(t, err)
+	return server, api
+}
+
+func TestAPI_fakeExec(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, api.Exec("", buffer))
+	assert.Equal(t, 0, buffer.Len())
+	assert.NotNil(t, api.Exec("\n", buffer))
+
+	assert.Nil(t, api.ExecEval("", buffer))
+	assert.Nil(t, api.ExecEval("num-slots", buffer))
+	assert.Equal(t, "PyON 1 num-slots\n1\n---", buffer.String())
+
+	err := api.Exec("a", buffer)
+	assert.True(t, errors.Is(err, fahapi.ErrUnknownCommand), err)
+	var commandErr *fahapi.CommandError
+	require.True(t, errors.As(err, &commandErr))
+	assert.Equal(t, "a", commandErr.Command)
+
+	result, err := api.Help()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, result)
+
+	uptime, err := api.Uptime()
+	assert.Nil(t, err)
+	assert.True(t, time.Duration(uptime) < time.Minute, uptime)
+}
+
+func TestAPI_fakeNoOps(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	coreURL, err := url.Parse("https://example.com/core")
+	require.Nil(t, err)
+
+	assert.Nil(t, api.Screensaver())
+	assert.Nil(t, api.DoCycle())
+	assert.Nil(t, api.DownloadCore("0xa7", coreURL))
+	assert.Nil(t, api.RequestID())
+	assert.Nil(t, api.RequestWS())
+	assert.Nil(t, api.WaitForUnits())
+	assert.Nil(t, api.Shutdown())
+
+	assert.Equal(
+		t,
+		[]string{
+			"screensaver",
+			"do-cycle",
+			"download-core 0xa7 https://example.com/core",
+			"request-id",
+			"request-ws",
+			"wait-for-units",
+			"shutdown",
+		},
+		server.Commands(),
+	)
+}
+
+func TestAPI_fakeSlots(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	server.AddSlot("gpu:0:0")
+
+	n, err := api.NumSlots()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+
+	require.Nil(t, api.PauseSlot(1))
+	slots, err := api.SlotInfo()
+	require.Nil(t, err)
+	require.Len(t, slots, 2)
+	assert.Equal(t, "RUNNING", slots[0].Status)
+	assert.Equal(t, "01", slots[1].ID)
+	assert.Equal(t, "PAUSED", slots[1].Status)
+	assert.Equal(t, "gpu:0:0", slots[1].Description)
+
+	assert.Nil(t, api.PauseAll())
+	assert.Nil(t, api.UnpauseSlot(0))
+	assert.Equal(t, "RUNNING", server.Slots()[0].Status)
+	assert.Equal(t, "PAUSED", server.Slots()[1].Status)
+	assert.Nil(t, api.UnpauseAll())
+	assert.Equal(t, "RUNNING", server.Slots()[1].Status)
+
+	assert.Nil(t, api.OnIdle(0))
+	assert.Equal(t, "true", server.Slots()[0].Options["idle"])
+	assert.Nil(t, api.AlwaysOn(0))
+	assert.Equal(t, "false", server.Slots()[0].Options["idle"])
+	assert.Nil(t, api.OnIdleAll())
+
+	assert.Nil(t, api.FinishSlot(1))
+	assert.Equal(t, "FINISHING", server.Slots()[1].Status)
+	assert.Nil(t, api.FinishAll())
+
+	options := &fahapi.SlotOptions{}
+	require.Nil(t, api.SlotOptionsGet(0, options))
+	assert.Equal(t, "0", options.MachineID)
+	err = api.SlotOptionsGet(-1, options)
+	assert.True(t, errors.Is(err, fahapi.ErrInvalidSlot), err)
+	err = api.PauseSlot(5)
+	assert.True(t, errors.Is(err, fahapi.ErrInvalidSlot), err)
+
+	require.Nil(t, api.SlotOptionsSet(0, "paused", true))
+	require.Nil(t, api.SlotOptionsGet(0, options))
+	assert.Equal(t, fahapi.StringBool(true), options.Paused)
+	assert.Equal(t, "PAUSED", server.Slots()[0].Status)
+
+	require.Nil(t, api.SlotOptionsUpdate(0, fahapi.SlotChanges{
+		"paused":               false,
+		"next-unit-percentage": 100,
+		"core-priority":        "low",
+	}))
+	assert.Equal(
+		t,
+		"slot-options 0 core-priority low next-unit-percentage 100 paused false",
+		server.Commands()[len(server.Commands())-1],
+	)
+	require.Nil(t, api.SlotOptionsGet(0, options))
+	assert.Equal(t, fahapi.StringBool(false), options.Paused)
+	assert.Equal(t, fahapi.StringInt(100), options.NextUnitPercentage)
+	assert.Equal(t, "low", options.CorePriority)
+	assert.Equal(t, "RUNNING", server.Slots()[0].Status)
+
+	require.Nil(t, api.SlotOptionsReset(0, "next-unit-percentage", "core-priority"))
+	require.Nil(t, api.SlotOptionsGet(0, options))
+	assert.Equal(t, fahapi.StringInt(90), options.NextUnitPercentage)
+	assert.Equal(t, "idle", options.CorePriority)
+
+	require.Nil(t, api.SlotOptionsSet(0, "extra-core-args", "-a -b"))
+	assert.Equal(t, "-a -b", server.Slots()[0].Options["extra-core-args"])
+	assert.NotNil(t, api.SlotOptionsSet(0, "a b", true))
+	assert.NotNil(t, api.SlotOptionsSet(0, "cpu_usage", 50))
+	assert.NotNil(t, api.SlotOptionsSet(0, "cpu-usage", 101))
+	assert.NotNil(t, api.SlotOptionsSet(0, "paused", nil))
+
+	require.Nil(t, api.SlotDelete(1))
+	assert.Len(t, server.Slots(), 1)
+}
+
+func TestAPI_fakeVerified(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	server.AddSlot("cpu:1")
+
+	require.Nil(t, api.PauseSlotVerified(1, time.Second))
+	assert.Equal(t, "PAUSED", server.Slots()[1].Status)
+	require.Nil(t, api.UnpauseSlotVerified(1, time.Second))
+	assert.Equal(t, "RUNNING", server.Slots()[1].Status)
+	require.Nil(t, api.FinishSlotVerified(1, time.Second))
+	require.Nil(t, api.OnIdleVerified(0, time.Second))
+	assert.True(t, server.Slots()[0].Idle)
+	require.Nil(t, api.AlwaysOnVerified(0, time.Second))
+	assert.False(t, server.Slots()[0].Idle)
+	require.Nil(t, api.SlotDeleteVerified(1, time.Second))
+	assert.Len(t, server.Slots(), 1)
+
+	n := len(server.Commands())
+	err := api.PauseSlotVerified(1, time.Second)
+	assert.True(t, errors.Is(err, fahapi.ErrInvalidSlot), err)
+	assert.NotContains(t, server.Commands()[n:], "pause 1")
+
+	// The client ignores the command
+	server.Handle("pause", func([]string) (string, error) {
+		return "", nil
+	})
+	err = api.PauseSlotVerified(0, 0)
+	var verifyErr *fahapi.VerifyError
+	require.True(t, errors.As(err, &verifyErr), err)
+	assert.Equal(t, "pause 0", verifyErr.Command)
+	assert.Equal(t, "RUNNING", verifyErr.Slot.Status)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NotNil(t, api.PauseSlotVerifiedContext(ctx, 0, time.Second))
+}
+
+func TestAPI_fakeSlotAddModify(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	id, err := api.SlotAdd(fahapi.SlotTypeGPU, fahapi.SlotChanges{
+		fahapi.SlotGPUIndex:    1,
+		fahapi.SlotOpenCLIndex: 0,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 1, id)
+	assert.Equal(t, "gpu:1:fahtest GPU", server.Slots()[1].Description)
+	assert.Equal(t, "0", server.Slots()[1].Options["opencl-index"])
+
+	_, err = api.SlotAdd(fahapi.SlotTypeCPU, fahapi.SlotChanges{"paused": nil})
+	assert.NotNil(t, err)
+	_, err = api.SlotAdd(fahapi.SlotTypeCPU, fahapi.SlotChanges{"cpu_usage": 50})
+	assert.NotNil(t, err)
+
+	require.Nil(t, api.SlotModify(id, fahapi.SlotTypeCPU, fahapi.SlotChanges{
+		fahapi.SlotCPUs:        2,
+		fahapi.SlotOpenCLIndex: nil,
+	}))
+	assert.Equal(t, "cpu:2", server.Slots()[1].Description)
+	assert.Equal(t, "-1", server.Slots()[1].Options["opencl-index"])
+	assert.Equal(t, "slot-modify 1 cpu cpus=2 opencl-index!", server.Commands()[3])
+	assert.NotNil(t, api.SlotModify(id, fahapi.SlotTypeCPU, fahapi.SlotChanges{"cpus": "a"}))
+
+	id, err = api.SlotAdd(fahapi.SlotTypeCPU, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, id)
+}
+
+func TestAPI_fakeQueue(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	units, err := api.QueueInfo()
+	require.Nil(t, err)
+	assert.Equal(t, server.Units(), units)
+
+	ppd, err := api.PPD()
+	assert.Nil(t, err)
+	assert.Equal(t, float64(units[0].PPD), ppd)
+
+	info := &fahapi.SimulationInfo{}
+	require.Nil(t, api.SimulationInfo(0, info))
+	assert.Equal(t, units[0].Project, info.Project)
+	assert.Equal(t, "Anonymous", info.User)
+
+	server.SetUnits(nil)
+	units, err = api.QueueInfo()
+	assert.Nil(t, err)
+	assert.Empty(t, units)
+}
+
+func TestAPI_fakeOptions(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	configured, err := api.Configured()
+	assert.Nil(t, err)
+	assert.False(t, configured)
+
+	require.Nil(t, api.OptionsSet("user", "a"))
+	assert.Equal(t, "a", server.Option("user"))
+
+	configured, err = api.Configured()
+	assert.Nil(t, err)
+	assert.True(t, configured)
+
+	require.Nil(t, api.OptionsSet("power", fahapi.PowerFull))
+	options := &fahapi.Options{}
+	require.Nil(t, api.OptionsGet(options))
+	assert.Equal(t, fahapi.PowerFull, options.Power)
+	assert.Equal(t, "a", options.User)
+	assert.Equal(t, "log.txt", options.Log)
+
+	changed, err := api.OptionsChanged()
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{"power": "FULL", "user": "a"}, changed)
+
+	withDefaults, err := api.OptionsWithDefaults()
+	require.Nil(t, err)
+	assert.Equal(t, "log.txt", withDefaults["log"])
+	assert.NotContains(t, withDefaults, "passkey")
+
+	options = &fahapi.Options{Log: "a"}
+	require.Nil(t, api.OptionsUpdate(
+		fahapi.OptionChanges{"team": 1, "power": nil, "cpus": 2},
+		options,
+	))
+	assert.Equal(t, "options cpus=2 power! team=1", server.Commands()[len(server.Commands())-1])
+	assert.Equal(t, fahapi.StringInt(1), options.Team)
+	assert.Equal(t, fahapi.PowerMedium, options.Power)
+	assert.Equal(t, fahapi.StringInt(2), options.Cpus)
+	assert.Equal(t, "a", options.Log)
+	assert.Equal(t, "medium", server.Option("power"))
+
+	require.Nil(t, api.OptionsReset(nil, "team", "user", "cpus"))
+	changed, err = api.OptionsChanged()
+	require.Nil(t, err)
+	assert.Empty(t, changed)
+
+	assert.NotNil(t, api.OptionsReset(nil, "a=b"))
+	assert.NotNil(t, api.OptionsSet("cpu_usage", 50))
+	assert.NotNil(t, api.OptionsSet("cpu-usage", 101))
+
+	cpuUsage := fahapi.StringInt(50)
+	user := `a "b"`
+	require.Nil(t, api.OptionsApply(&fahapi.OptionsPatch{CpuUsage: &cpuUsage, User: &user}, options))
+	assert.Equal(t, `options cpu-usage=50 "user=a \"b\""`, server.Commands()[len(server.Commands())-1])
+	assert.Equal(t, cpuUsage, options.CpuUsage)
+	assert.Equal(t, user, options.User)
+	assert.Equal(t, user, server.Option("user"))
+}
+
+func TestAPI_fakeInfo(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	info := &fahapi.Info{}
+	require.Nil(t, api.InfoStruct(info))
+	assert.Equal(t, "7.6.21", info.FAHClient.Version)
+	assert.Equal(t, fahapi.StringInt(4), info.System.CPUs)
+}
+
+func TestAPI_fakeLog(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	server.AppendLog("a\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lines, err := api.SubscribeLog(ctx)
+	require.Nil(t, err)
+	assert.Equal(t, fahapi.LogLine("a"), <-lines)
+
+	server.AppendLog("b\nc\n")
+	assert.Equal(t, fahapi.LogLine("b"), <-lines)
+	assert.Equal(t, fahapi.LogLine("c"), <-lines)
+}
+
+func TestAPI_fakeUpdates(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	slots, err := api.WatchSlotInfo(ctx, time.Second)
+	require.Nil(t, err)
+	assert.Len(t, <-slots, 1)
+
+	server.AddSlot("cpu:1")
+	require.Nil(t, api.UpdatesReset())
+	assert.Len(t, <-slots, 2)
+
+	list, err := api.UpdatesList()
+	assert.Nil(t, err)
+	assert.Equal(t, "0 1 $slot-info", list)
+
+	assert.Nil(t, api.UpdatesClear())
+}
+
+func TestAPI_fakeAuth(t *testing.T) {
+	server := fahtest.NewServer()
+	defer server.Close()
+	server.SetPassword("a b")
+
+	api, err := fahapi.DialContext(context.Background(), server.Addr())
+	require.Nil(t, err)
+	defer api.Close()
+
+	_, err = api.NumSlots()
+	assert.True(t, errors.Is(err, fahapi.ErrAuthRequired), err)
+	assert.NotNil(t, api.Auth("a"))
+	require.Nil(t, api.Auth("a b"))
+
+	// Authenticates again after the client restarts
+	server.DisconnectAll()
+	_, err = api.NumSlots()
+	assert.True(t, errors.Is(err, fahapi.ErrDisconnected), err)
+	n, err := api.NumSlots()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestAPI_fakeReconcile(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	server.AddSlot("cpu:2")
+	server.SetOption("user", "a")
+	require.Nil(t, api.SlotOptionsSet(0, "core-priority", "low"))
+
+	config := &fahapi.Config{
+		Options: fahapi.OptionChanges{"user": nil, "team": 1, "power": "light", "cpus": -1},
+		Slots: []fahapi.SlotConfig{
+			{
+				ID:      0,
+				Type:    fahapi.SlotTypeCPU,
+				Options: fahapi.SlotChanges{"core-priority": nil, "cpus": 3},
+			},
+			{
+				ID:      5,
+				Type:    fahapi.SlotTypeGPU,
+				Options: fahapi.SlotChanges{fahapi.SlotGPUIndex: 0, "paused": nil},
+			},
+		},
+	}
+
+	diff, err := api.Reconcile(config, true)
+	require.Nil(t, err)
+	assert.Equal(
+		t,
+		&fahapi.Diff{
+			Options: []fahapi.OptionDiff{
+				{Name: "power", Current: "medium", Desired: "LIGHT"},
+				{Name: "team", Current: "0", Desired: "1"},
+				{Name: "user", Current: "a"},
+			},
+			AddSlots: config.Slots[1:],
+			ModifySlots: []fahapi.SlotDiff{{
+				ID: 0,
+				Options: []fahapi.OptionDiff{
+					{Name: "core-priority", Current: "low"},
+					{Name: "cpus", Current: "-1", Desired: 3},
+				},
+			}},
+			DeleteSlots: []int{1},
+		},
+		diff,
+	)
+	assert.Equal(
+		t,
+		"option power: \"medium\" -> \"LIGHT\"\n"+
+			"option team: \"0\" -> \"1\"\n"+
+			"option user: \"a\" -> default\n"+
+			"delete slot 1\n"+
+			"modify slot 0: core-priority: \"low\" -> default\n"+
+			"modify slot 0: cpus: \"-1\" -> \"3\"\n"+
+			"add gpu slot gpu-index=0 paused!\n",
+		diff.String(),
+	)
+	assert.Len(t, server.Slots(), 2) // Dry run
+
+	diff, err = api.Reconcile(config, false)
+	require.Nil(t, err)
+	assert.False(t, diff.Empty())
+
+	slots := server.Slots()
+	require.Len(t, slots, 2)
+	assert.Equal(t, "cpu:3", slots[0].Description)
+	assert.Equal(t, "idle", slots[0].Options["core-priority"])
+	assert.Equal(t, "gpu:0:fahtest GPU", slots[1].Description)
+	assert.Equal(t, "Anonymous", server.Option("user"))
+	assert.Equal(t, "LIGHT", server.Option("power"))
+	require.Len(t, diff.AddSlots, 1)
+	assert.Equal(t, slots[1].ID, diff.AddSlots[0].ID)
+
+	// The added slot has a different ID than the config, but it is not replaced
+	diff, err = api.Reconcile(config, false)
+	require.Nil(t, err)
+	assert.True(t, diff.Empty(), diff)
+	assert.Equal(t, slots, server.Slots())
+
+	// The added slot is modified instead of replaced if the config changes
+	config.Slots[1].Options["next-unit-percentage"] = 100
+	diff, err = api.Reconcile(config, true)
+	require.Nil(t, err)
+	assert.Equal(
+		t,
+		&fahapi.Diff{
+			ModifySlots: []fahapi.SlotDiff{{
+				ID: slots[1].ID,
+				Options: []fahapi.OptionDiff{
+					{Name: "next-unit-percentage", Current: "90", Desired: 100},
+				},
+			}},
+		},
+		diff,
+	)
+}
+
+func TestAPI_fakeReconcileNoID(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	config := &fahapi.Config{}
+	require.Nil(t, json.Unmarshal([]byte(`{"slots":[{"type":"cpu"},{"type":"gpu"}]}`), config))
+	assert.Equal(t, fahapi.NoSlotID, config.Slots[0].ID)
+
+	diff, err := api.Reconcile(config, true)
+	require.Nil(t, err)
+	assert.Equal(t, &fahapi.Diff{AddSlots: config.Slots[1:]}, diff)
+
+	_, err = api.Reconcile(config, false)
+	require.Nil(t, err)
+	slots := server.Slots()
+	require.Len(t, slots, 2)
+	assert.Equal(t, "cpu:4", slots[0].Description)
+	assert.Equal(t, "gpu:0:fahtest GPU", slots[1].Description)
+
+	_, err = api.Reconcile(&fahapi.Config{Slots: []fahapi.SlotConfig{
+		{ID: 0, Type: fahapi.SlotTypeCPU},
+		{ID: 0, Type: fahapi.SlotTypeGPU},
+	}}, true)
+	assert.NotNil(t, err)
+}
+
+func TestAPI_fakeExportImport(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	server.AddSlot("gpu:0:fahtest GPU")
+	require.Nil(t, api.OptionsSet("user", "a"))
+	require.Nil(t, api.SlotOptionsSet(0, "core-priority", "low"))
+
+	config, err := api.ExportConfig()
+	require.Nil(t, err)
+
+	b, err := json.Marshal(config)
+	require.Nil(t, err)
+	assert.Equal(
+		t,
+		`{"options":{"user":"a"},"slots":[`+
+			`{"id":0,"type":"cpu","options":{"core-priority":"low"}},`+
+			`{"id":1,"type":"gpu","options":{}}]}`,
+		string(b),
+	)
+
+	require.Nil(t, api.Save(""))
+	require.Nil(t, api.Save("backup config.xml"))
+	assert.Equal(
+		t,
+		[]string{"save", `save "backup config.xml"`},
+		server.Commands()[len(server.Commands())-2:],
+	)
+
+	// Experiment
+	require.Nil(t, api.OptionsSet("team", 1))
+	require.Nil(t, api.SlotOptionsSet(0, "next-unit-percentage", 100))
+	require.Nil(t, api.SlotDelete(1))
+
+	imported := &fahapi.Config{}
+	require.Nil(t, json.Unmarshal(b, imported))
+	diff, err := api.ImportConfig(imported, false)
+	require.Nil(t, err)
+	assert.False(t, diff.Empty())
+
+	assert.Equal(t, "0", server.Option("team"))
+	assert.Equal(t, "a", server.Option("user"))
+	assert.Equal(t, "90", server.Slots()[0].Options["next-unit-percentage"])
+	assert.Equal(t, "low", server.Slots()[0].Options["core-priority"])
+	assert.Len(t, server.Slots(), 2)
+}
+
+func TestAPI_fakeImportTwice(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	server.AddSlot("gpu:0:fahtest GPU")
+	server.AddSlot("cpu:1")
+
+	config, err := api.ExportConfig()
+	require.Nil(t, err)
+
+	// The slot is added with a new ID because slot 2 exists
+	require.Nil(t, api.SlotDelete(1))
+	diff, err := api.ImportConfig(config, false)
+	require.Nil(t, err)
+	require.Len(t, diff.AddSlots, 1)
+	assert.Equal(t, 3, diff.AddSlots[0].ID)
+
+	slots := server.Slots()
+	diff, err = api.ImportConfig(config, false)
+	require.Nil(t, err)
+	assert.True(t, diff.Empty(), diff)
+	assert.Equal(t, slots, server.Slots())
+
+	// Options of the added slot that are not in the config are reset
+	require.Nil(t, api.SlotOptionsSet(3, "next-unit-percentage", 100))
+	diff, err = api.ImportConfig(config, false)
+	require.Nil(t, err)
+	assert.Equal(
+		t,
+		&fahapi.Diff{
+			ModifySlots: []fahapi.SlotDiff{{
+				ID:      3,
+				Options: []fahapi.OptionDiff{{Name: "next-unit-percentage", Current: "100"}},
+			}},
+		},
+		diff,
+	)
+	assert.Equal(t, slots, server.Slots())
+}
+
+func TestAPI_fakeWatchQueueInfoTwice(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first, err := api.WatchQueueInfo(ctx, time.Hour)
+	require.Nil(t, err)
+	<-first
+
+	second, err := api.WatchQueueInfo(ctx, time.Hour)
+	require.Nil(t, err)
+	<-second
+
+	// The result of the second update is not received by the first watcher
+	select {
+	case <-first:
+		t.Error("received the result of another watcher")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	// Each watcher receives one result after a reset
+	require.Nil(t, api.UpdatesReset())
+	<-first
+	<-second
+	select {
+	case <-first:
+		t.Error("received the result of another watcher")
+	case <-second:
+		t.Error("received the result of another watcher")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	cancel()
+	for range first {
+	}
+	for range second {
+	}
+}
+
+func TestAPI_fakeWatchEvents(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	// The handlers signal when the initial snapshots are taken, so that the changes are not in them
+	var mutex sync.Mutex
+	units := server.Units()
+	units[0].State = "SEND"
+	slots := []fahapi.SlotInfo{{ID: "00", Status: "RUNNING"}}
+	called := make(chan struct{}, 10)
+	server.Handle("queue-info", func([]string) (string, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		called <- struct{}{}
+		b, err := fahapi.MarshalPyON("units", units)
+		return string(b), err
+	})
+	server.Handle("slot-info", func([]string) (string, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		called <- struct{}{}
+		b, err := fahapi.MarshalPyON("slots", slots)
+		return string(b), err
+	})
+
+	// The credit of a previous unit with the same queue ID is not an event
+	server.AppendLog("12:00:00:WU00:FS00:Final credit estimate, 1.00 points\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := api.WatchEvents(ctx, time.Hour)
+	require.Nil(t, err)
+	<-called
+	<-called
+
+	mutex.Lock()
+	units[0].PercentDone = "90.00%"
+	slots[0].Status = "PAUSED"
+	mutex.Unlock()
+	require.Nil(t, api.UpdatesReset())
+
+	received := map[fahapi.WatchEventType]fahapi.WatchEvent{}
+	for len(received) < 2 {
+		event := <-events
+		received[event.Type] = event
+	}
+	assert.Equal(t, "90.00%", received[fahapi.EventWUProgress].Unit.PercentDone)
+	assert.Equal(t, "00", received[fahapi.EventSlotPaused].Slot.ID)
+
+	server.AppendLog("12:00:01:WU00:FS00:Final credit estimate, 21356.00 points\n")
+	event := <-events
+	assert.Equal(t, fahapi.EventWUUploaded, event.Type)
+	event = <-events
+	assert.Equal(t, fahapi.EventWUCredited, event.Type)
+	assert.Equal(t, units[0].Unit, event.Unit.Unit)
+	assert.Equal(t, 21356.0, event.Credit)
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestAPI_fakePipelining(t *testing.T) {
+	server := fahtest.NewServer()
+	defer server.Close()
+
+	api, err := fahapi.DialContext(
+		context.Background(),
+		server.Addr(),
+		fahapi.WithPipelining(),
+	)
+	require.Nil(t, err)
+	defer api.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(session *fahapi.API, i int) {
+			defer wg.Done()
+
+			for j := 0; j < 10; j++ {
+				n, err := session.NumSlots()
+				assert.Nil(t, err, i)
+				assert.Equal(t, 1, n, i)
+
+				slots, err := session.SlotInfo()
+				assert.Nil(t, err, i)
+				assert.Len(t, slots, 1, i)
+			}
+		}(api.Session(), i)
+	}
+	wg.Wait()
+
+	// A cancelled command returns without waiting, and its response is discarded
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server.Handle("slow", func([]string) (string, error) {
+		close(started)
+		<-release
+		return "slow", nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	slowErr := make(chan error)
+	go func() {
+		slowErr <- api.ExecContext(ctx, "slow", &bytes.Buffer{})
+	}()
+
+	<-started
+	cancel()
+	err = <-slowErr
+	assert.Equal(t, context.Canceled, errors.Cause(err), err)
+
+	close(release)
+	buffer := &bytes.Buffer{}
+	require.Nil(t, api.Session().Exec("num-slots", buffer))
+	assert.Equal(t, "PyON 1 num-slots\n1\n---", buffer.String())
+
+	// The next command reconnects. It fails if it was sent before the loss was noticed.
+	server.DisconnectAll()
+	if _, err := api.NumSlots(); err != nil {
+		var disconnectErr *fahapi.DisconnectError
+		require.True(t, errors.As(err, &disconnectErr), err)
+		assert.True(t, disconnectErr.Reconnected)
+	}
+	n, err := api.Session().NumSlots()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestAPI_fakeSession(t *testing.T) {
+	server, api := dialFake(t)
+	defer server.Close()
+	defer api.Close()
+
+	// Sessions of a connection that is not pipelined take turns
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(session *fahapi.API, i int) {
+			defer wg.Done()
+
+			n, err := session.NumSlots()
+			assert.Nil(t, err, i)
+			assert.Equal(t, 1, n, i)
+		}(api.Session(), i)
+	}
+	wg.Wait()
+}
+
+func TestPool_fake(t *testing.T) {
+	server := fahtest.NewServer()
+	defer server.Close()
+	server.SetOption("max-connections", "2")
+
+	var dials int32
+	dial := func(ctx context.Context, addr string) (net.Conn, error) {
+		atomic.AddInt32(&dials, 1)
+		return (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+
+	pool, err := fahapi.DialPool(context.Background(), server.Addr(), 5, fahapi.WithDialFunc(dial))
+	require.Nil(t, err)
+	defer pool.Close()
+	pool.IdleCheck = 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			n, err := pool.NumSlots()
+			assert.Nil(t, err, i)
+			assert.Equal(t, 1, n, i)
+
+			slots, err := pool.SlotInfo()
+			assert.Nil(t, err, i)
+			assert.Len(t, slots, 1, i)
+		}(i)
+	}
+	wg.Wait()
+	assert.True(t, atomic.LoadInt32(&dials) <= 2, dials)
+
+	// Idle connections that were lost are replaced
+	server.DisconnectAll()
+	n, err := pool.NumSlots()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	// Callers wait for a connection while all of them are lent
+	started := make(chan struct{})
+	release := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		go pool.Do(context.Background(), func(api *fahapi.API) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		})
+	}
+	<-started
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	_, err = pool.NumSlotsContext(ctx)
+	assert.Equal(t, context.DeadlineExceeded, errors.Cause(err), err)
+
+	close(release)
+	n, err = pool.NumSlots()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestPool_fakeWatch(t *testing.T) {
+	server := fahtest.NewServer()
+	defer server.Close()
+
+	pool, err := fahapi.DialPool(context.Background(), server.Addr(), 1)
+	require.Nil(t, err)
+	defer pool.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	units, err := pool.WatchQueueInfo(ctx, time.Hour)
+	require.Nil(t, err)
+	assert.Equal(t, server.Units(), <-units)
+
+	// The connection is lent until ctx is done
+	timeout, cancelTimeout := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancelTimeout()
+	_, err = pool.NumSlotsContext(timeout)
+	assert.Equal(t, context.DeadlineExceeded, errors.Cause(err), err)
+
+	cancel()
+	n, err := pool.NumSlots()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	assert.NotNil(t, pool.UpdatesAdd(0, time.Second, "$queue-info"))
+	_, err = pool.LogUpdates(fahapi.LogUpdatesStart)
+	assert.NotNil(t, err)
+	assert.Nil(t, pool.Do(context.Background(), func(api *fahapi.API) error {
+		return api.UpdatesAdd(0, time.Second, "$queue-info")
+	}))
+}
+
+func TestPool_fakeWithPassword(t *testing.T) {
+	server := fahtest.NewServer()
+	defer server.Close()
+	server.SetPassword("a")
+
+	var dials int32
+	dial := func(ctx context.Context, addr string) (net.Conn, error) {
+		atomic.AddInt32(&dials, 1)
+		return (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+
+	pool, err := fahapi.DialPool(
+		context.Background(),
+		server.Addr(),
+		2,
+		fahapi.WithDialFunc(dial),
+		fahapi.WithPassword("a"),
+	)
+	require.Nil(t, err)
+	defer pool.Close()
+	pool.IdleCheck = 0
+
+	for i := 0; i < 5; i++ {
+		n, err := pool.NumSlots()
+		assert.Nil(t, err, i)
+		assert.Equal(t, 1, n, i)
+	}
+
+	// Each connection is authenticated once
+	auths := 0
+	for _, command := range server.Commands() {
+		if strings.HasPrefix(command, "auth ") {
+			assert.Equal(t, `auth "a"`, command)
+			auths++
+		}
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&dials))
+	assert.Equal(t, 1, auths)
+}
+
+func TestPool_fakeAuth(t *testing.T) {
+	server := fahtest.NewServer()
+	defer server.Close()
+	server.SetPassword("a")
+
+	pool, err := fahapi.DialPool(context.Background(), server.Addr(), 2, fahapi.WithPassword("a"))
+	require.Nil(t, err)
+	defer pool.Close()
+
+	assert.NotNil(t, pool.Auth("b"))
+	require.Nil(t, pool.Auth("a"))
+
+	n, err := pool.NumSlots()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+}
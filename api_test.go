@@ -147,50 +147,6 @@ func TestParseLog(t *testing.T) {
 	}
 }
 
-func TestParsePyONString(t *testing.T) {
-	tests := []struct {
-		s           string
-		expected    string
-		expectError bool
-	}{
-		{
-			``,
-			"",
-			true,
-		},
-		{
-			`""`,
-			"",
-			false,
-		},
-		{
-			`"\n\"\\\x01"`,
-			"\n\"\\\x01",
-			false,
-		},
-		{
-			`"a\x01a"`,
-			"a\x01a",
-			false,
-		},
-	}
-
-	for i, test := range tests {
-		result, err := ParsePyONString([]byte(test.s))
-		assert.Equal(t, test.expected, result, i)
-		checkerror.Check(t, test.expectError, err, i)
-	}
-}
-
-func BenchmarkParsePyONString(b *testing.B) {
-	// BenchmarkParsePyONString-8   	 1555113	       762 ns/op
-	var result string
-	for i := 0; i < b.N; i++ {
-		result, _ = ParsePyONString([]byte("a\x01\\n"))
-	}
-	_ = result
-}
-
 func (a *APITestSuite) TestScreensaver() {
 	if !doAllTests {
 		return
@@ -346,108 +302,3 @@ func (a *APITestSuite) TestUptime() {
 	_, err := a.api.Uptime()
 	assert.Nil(a.T(), err)
 }
-
-func TestUnmarshalPyON(t *testing.T) {
-	tests := []struct {
-		s           string
-		expected    interface{}
-		expectError bool
-	}{
-		{
-			"",
-			nil,
-			true,
-		},
-		{
-			"PyON\n---",
-			nil,
-			true,
-		},
-		{
-			"PyON\n\n---",
-			nil,
-			true,
-		},
-		{
-			"PyON\n1\n---",
-			1.0,
-			false,
-		},
-		{
-			"PyON\nTrue\n---",
-			true,
-			false,
-		},
-		{
-			"PyON\n{\"\": None}\n---",
-			map[string]interface{}{"": ""},
-			false,
-		},
-	}
-
-	for i, test := range tests {
-		var dst interface{}
-		checkerror.Check(t, test.expectError, UnmarshalPyON([]byte(test.s), &dst), i)
-		assert.Equal(t, test.expected, dst, i)
-	}
-}
-
-func TestReplace(t *testing.T) {
-	tests := []struct {
-		b        []byte
-		old      []byte
-		new      []byte
-		expected []byte
-	}{
-		{
-			nil,
-			nil,
-			nil,
-			nil,
-		},
-		{
-			[]byte("a"),
-			[]byte("a"),
-			[]byte("a"),
-			[]byte("a"),
-		},
-		{
-			[]byte("b"),
-			[]byte("aa"),
-			[]byte("aa"),
-			[]byte("b"),
-		},
-		{
-			[]byte("a"),
-			[]byte("a"),
-			[]byte("b"),
-			[]byte("b"),
-		},
-		{
-			[]byte("ac"),
-			[]byte("a"),
-			[]byte("b"),
-			[]byte("bc"),
-		},
-		{
-			[]byte("ca"),
-			[]byte("a"),
-			[]byte("b"),
-			[]byte("cb"),
-		},
-	}
-
-	for i, test := range tests {
-		replace(test.b, test.old, test.new)
-		assert.Equal(t, test.expected, test.b, i)
-	}
-}
-
-func BenchmarkUnparshalPyOn(b *testing.B) {
-	// BenchmarkUnparshalPyOn-8   	 4463941	       268 ns/op
-	var result struct{}
-	for i := 0; i < b.N; i++ {
-		_ = UnmarshalPyON([]byte("PyON\n{\"a\":\"b\"}\n---"), &result)
-	}
-	_ = result
-}
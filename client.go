@@ -0,0 +1,98 @@
+package fahapi
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log"
+	"time"
+)
+
+//go:generate go run ./internal/cmd/genclient
+
+var (
+	_ Client = (*API)(nil)
+	_ Client = (*Pool)(nil)
+)
+
+// Interceptor is called for each method call of a Client returned by Intercept. method is the
+// name of the method, and call runs it. ctx is the context argument of the method, or
+// context.Background() for methods without one; call passes its ctx argument to the method, or to
+// the Context variant of a method without a context argument, so an Interceptor can add a timeout.
+// The deprecated Finish has no Context variant and ignores it. Methods that return a channel, like
+// SubscribeLog and the Watch methods, get the ctx of the caller instead, because the channel is
+// used after call returns. call can be run more than once, and the last results are returned.
+type Interceptor func(
+	ctx context.Context,
+	method string,
+	call func(ctx context.Context) error,
+) error
+
+// Intercept returns a Client that calls interceptor for each method call, which runs the method
+// of client.
+func Intercept(client Client, interceptor Interceptor) Client {
+	return &interceptedClient{next: client, intercept: interceptor}
+}
+
+type interceptedClient struct {
+	next      Client
+	intercept Interceptor
+}
+
+// LoggingClient returns a Client that logs each method call of client, its duration and its error
+// to logger.
+func LoggingClient(client Client, logger *log.Logger) Client {
+	return Intercept(client, func(
+		ctx context.Context,
+		method string,
+		call func(ctx context.Context) error,
+	) error {
+		start := time.Now()
+		err := call(ctx)
+		if err != nil {
+			logger.Printf("%s (%s): %s", method, time.Since(start), err)
+		} else {
+			logger.Printf("%s (%s)", method, time.Since(start))
+		}
+		return err
+	})
+}
+
+// MetricsClient returns a Client that calls observe after each method call of client with the name
+// of the method, its duration and its error.
+func MetricsClient(
+	client Client,
+	observe func(method string, duration time.Duration, err error),
+) Client {
+	return Intercept(client, func(
+		ctx context.Context,
+		method string,
+		call func(ctx context.Context) error,
+	) error {
+		start := time.Now()
+		err := call(ctx)
+		observe(method, time.Since(start), err)
+		return err
+	})
+}
+
+// RetryClient returns a Client that calls each method of client up to attempts times while it
+// returns a *DisconnectError whose Retry() is true, which means that the command was not sent and
+// the connection was reestablished.
+func RetryClient(client Client, attempts int) Client {
+	return Intercept(client, func(
+		ctx context.Context,
+		method string,
+		call func(ctx context.Context) error,
+	) error {
+		var err error
+		for attempt := 0; attempt < attempts; attempt++ {
+			err = call(ctx)
+
+			var disconnectErr *DisconnectError
+			if !errors.As(err, &disconnectErr) || !disconnectErr.Retry() {
+				return err
+			}
+		}
+		return err
+	})
+}
@@ -0,0 +1,1097 @@
+// Code generated by genclient from the methods of API; DO NOT EDIT.
+
+package fahapi
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"time"
+)
+
+// Client has the command methods of API. It is implemented by API, Pool, the Client returned by
+// Intercept, and fahtest.MockClient.
+type Client interface {
+	AlwaysOn(slot int) error
+	AlwaysOnContext(ctx context.Context, slot int) error
+	AlwaysOnVerified(slot int, timeout time.Duration) error
+	AlwaysOnVerifiedContext(ctx context.Context, slot int, timeout time.Duration) error
+	Auth(password string) error
+	AuthContext(ctx context.Context, password string) error
+	Configured() (bool, error)
+	ConfiguredContext(ctx context.Context) (bool, error)
+	Diff(config *Config) (*Diff, error)
+	DiffContext(ctx context.Context, config *Config) (*Diff, error)
+	DoCycle() error
+	DoCycleContext(ctx context.Context) error
+	DownloadCore(coreType string, url *url.URL) error
+	DownloadCoreContext(ctx context.Context, coreType string, url *url.URL) error
+	Exec(command string, buffer *bytes.Buffer) error
+	ExecContext(ctx context.Context, command string, buffer *bytes.Buffer) error
+	ExecEval(command string, buffer *bytes.Buffer) error
+	ExecEvalContext(ctx context.Context, command string, buffer *bytes.Buffer) error
+	ExportConfig() (*Config, error)
+	ExportConfigContext(ctx context.Context) (*Config, error)
+	Finish(slot int) error
+	FinishAll() error
+	FinishAllContext(ctx context.Context) error
+	FinishSlot(slot int) error
+	FinishSlotContext(ctx context.Context, slot int) error
+	FinishSlotVerified(slot int, timeout time.Duration) error
+	FinishSlotVerifiedContext(ctx context.Context, slot int, timeout time.Duration) error
+	Help() (string, error)
+	HelpContext(ctx context.Context) (string, error)
+	ImportConfig(config *Config, dryRun bool) (*Diff, error)
+	ImportConfigContext(ctx context.Context, config *Config, dryRun bool) (*Diff, error)
+	Info() ([][]interface{}, error)
+	InfoContext(ctx context.Context) ([][]interface{}, error)
+	InfoStruct(dst *Info) error
+	InfoStructContext(ctx context.Context, dst *Info) error
+	LogUpdates(arg LogUpdatesArg) (string, error)
+	LogUpdatesContext(ctx context.Context, arg LogUpdatesArg) (string, error)
+	NumSlots() (int, error)
+	NumSlotsContext(ctx context.Context) (int, error)
+	OnIdle(slot int) error
+	OnIdleAll() error
+	OnIdleAllContext(ctx context.Context) error
+	OnIdleContext(ctx context.Context, slot int) error
+	OnIdleVerified(slot int, timeout time.Duration) error
+	OnIdleVerifiedContext(ctx context.Context, slot int, timeout time.Duration) error
+	OptionsApply(patch *OptionsPatch, dst *Options) error
+	OptionsApplyContext(ctx context.Context, patch *OptionsPatch, dst *Options) error
+	OptionsChanged() (map[string]string, error)
+	OptionsChangedContext(ctx context.Context) (map[string]string, error)
+	OptionsGet(dst *Options) error
+	OptionsGetContext(ctx context.Context, dst *Options) error
+	OptionsReset(dst *Options, keys ...string) error
+	OptionsResetContext(ctx context.Context, dst *Options, keys ...string) error
+	OptionsSet(key string, value interface{}) error
+	OptionsSetContext(ctx context.Context, key string, value interface{}) error
+	OptionsUpdate(changes OptionChanges, dst *Options) error
+	OptionsUpdateContext(ctx context.Context, changes OptionChanges, dst *Options) error
+	OptionsWithDefaults() (map[string]string, error)
+	OptionsWithDefaultsContext(ctx context.Context) (map[string]string, error)
+	PPD() (float64, error)
+	PPDContext(ctx context.Context) (float64, error)
+	PauseAll() error
+	PauseAllContext(ctx context.Context) error
+	PauseSlot(slot int) error
+	PauseSlotContext(ctx context.Context, slot int) error
+	PauseSlotVerified(slot int, timeout time.Duration) error
+	PauseSlotVerifiedContext(ctx context.Context, slot int, timeout time.Duration) error
+	QueueInfo() ([]SlotQueueInfo, error)
+	QueueInfoContext(ctx context.Context) ([]SlotQueueInfo, error)
+	Reconcile(config *Config, dryRun bool) (*Diff, error)
+	ReconcileContext(ctx context.Context, config *Config, dryRun bool) (*Diff, error)
+	RequestID() error
+	RequestIDContext(ctx context.Context) error
+	RequestWS() error
+	RequestWSContext(ctx context.Context) error
+	Save(path string) error
+	SaveContext(ctx context.Context, path string) error
+	Screensaver() error
+	ScreensaverContext(ctx context.Context) error
+	Shutdown() error
+	ShutdownContext(ctx context.Context) error
+	SimulationInfo(slot int, dst *SimulationInfo) error
+	SimulationInfoContext(ctx context.Context, slot int, dst *SimulationInfo) error
+	SlotAdd(slotType SlotType, changes SlotChanges) (int, error)
+	SlotAddContext(ctx context.Context, slotType SlotType, changes SlotChanges) (int, error)
+	SlotDelete(slot int) error
+	SlotDeleteContext(ctx context.Context, slot int) error
+	SlotDeleteVerified(slot int, timeout time.Duration) error
+	SlotDeleteVerifiedContext(ctx context.Context, slot int, timeout time.Duration) error
+	SlotInfo() ([]SlotInfo, error)
+	SlotInfoContext(ctx context.Context) ([]SlotInfo, error)
+	SlotModify(slot int, slotType SlotType, changes SlotChanges) error
+	SlotModifyContext(ctx context.Context, slot int, slotType SlotType, changes SlotChanges) error
+	SlotOptionsGet(slot int, dst *SlotOptions) error
+	SlotOptionsGetContext(ctx context.Context, slot int, dst *SlotOptions) error
+	SlotOptionsReset(slot int, keys ...string) error
+	SlotOptionsResetContext(ctx context.Context, slot int, keys ...string) error
+	SlotOptionsSet(slot int, key string, value interface{}) error
+	SlotOptionsSetContext(ctx context.Context, slot int, key string, value interface{}) error
+	SlotOptionsUpdate(slot int, changes SlotChanges) error
+	SlotOptionsUpdateContext(ctx context.Context, slot int, changes SlotChanges) error
+	SubscribeLog(ctx context.Context) (<-chan LogLine, error)
+	UnpauseAll() error
+	UnpauseAllContext(ctx context.Context) error
+	UnpauseSlot(slot int) error
+	UnpauseSlotContext(ctx context.Context, slot int) error
+	UnpauseSlotVerified(slot int, timeout time.Duration) error
+	UnpauseSlotVerifiedContext(ctx context.Context, slot int, timeout time.Duration) error
+	UpdatesAdd(id int, rate time.Duration, expression string) error
+	UpdatesAddContext(ctx context.Context, id int, rate time.Duration, expression string) error
+	UpdatesClear() error
+	UpdatesClearContext(ctx context.Context) error
+	UpdatesDel(id int) error
+	UpdatesDelContext(ctx context.Context, id int) error
+	UpdatesList() (string, error)
+	UpdatesListContext(ctx context.Context) (string, error)
+	UpdatesReset() error
+	UpdatesResetContext(ctx context.Context) error
+	Uptime() (FAHDuration, error)
+	UptimeContext(ctx context.Context) (FAHDuration, error)
+	WaitForUnits() error
+	WaitForUnitsContext(ctx context.Context) error
+	WatchEvents(ctx context.Context, rate time.Duration) (<-chan WatchEvent, error)
+	WatchOptions(ctx context.Context, rate time.Duration) (<-chan Options, error)
+	WatchQueueInfo(ctx context.Context, rate time.Duration) (<-chan []SlotQueueInfo, error)
+	WatchSlotInfo(ctx context.Context, rate time.Duration) (<-chan []SlotInfo, error)
+}
+
+func (c *interceptedClient) AlwaysOn(slot int) error {
+	return c.intercept(context.Background(), "AlwaysOn", func(ctx context.Context) error {
+		return c.next.AlwaysOnContext(ctx, slot)
+	})
+}
+
+func (c *interceptedClient) AlwaysOnContext(ctx context.Context, slot int) error {
+	return c.intercept(ctx, "AlwaysOnContext", func(ctx context.Context) error {
+		return c.next.AlwaysOnContext(ctx, slot)
+	})
+}
+
+func (c *interceptedClient) AlwaysOnVerified(slot int, timeout time.Duration) error {
+	return c.intercept(context.Background(), "AlwaysOnVerified", func(ctx context.Context) error {
+		return c.next.AlwaysOnVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+func (c *interceptedClient) AlwaysOnVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	return c.intercept(ctx, "AlwaysOnVerifiedContext", func(ctx context.Context) error {
+		return c.next.AlwaysOnVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+func (c *interceptedClient) Auth(password string) error {
+	return c.intercept(context.Background(), "Auth", func(ctx context.Context) error {
+		return c.next.AuthContext(ctx, password)
+	})
+}
+
+func (c *interceptedClient) AuthContext(ctx context.Context, password string) error {
+	return c.intercept(ctx, "AuthContext", func(ctx context.Context) error {
+		return c.next.AuthContext(ctx, password)
+	})
+}
+
+func (c *interceptedClient) Configured() (result bool, err error) {
+	err = c.intercept(context.Background(), "Configured", func(ctx context.Context) error {
+		result, err = c.next.ConfiguredContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) ConfiguredContext(ctx context.Context) (result bool, err error) {
+	err = c.intercept(ctx, "ConfiguredContext", func(ctx context.Context) error {
+		result, err = c.next.ConfiguredContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) Diff(config *Config) (result *Diff, err error) {
+	err = c.intercept(context.Background(), "Diff", func(ctx context.Context) error {
+		result, err = c.next.DiffContext(ctx, config)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) DiffContext(
+	ctx context.Context,
+	config *Config,
+) (result *Diff, err error) {
+	err = c.intercept(ctx, "DiffContext", func(ctx context.Context) error {
+		result, err = c.next.DiffContext(ctx, config)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) DoCycle() error {
+	return c.intercept(context.Background(), "DoCycle", func(ctx context.Context) error {
+		return c.next.DoCycleContext(ctx)
+	})
+}
+
+func (c *interceptedClient) DoCycleContext(ctx context.Context) error {
+	return c.intercept(ctx, "DoCycleContext", func(ctx context.Context) error {
+		return c.next.DoCycleContext(ctx)
+	})
+}
+
+func (c *interceptedClient) DownloadCore(coreType string, url *url.URL) error {
+	return c.intercept(context.Background(), "DownloadCore", func(ctx context.Context) error {
+		return c.next.DownloadCoreContext(ctx, coreType, url)
+	})
+}
+
+func (c *interceptedClient) DownloadCoreContext(
+	ctx context.Context,
+	coreType string,
+	url *url.URL,
+) error {
+	return c.intercept(ctx, "DownloadCoreContext", func(ctx context.Context) error {
+		return c.next.DownloadCoreContext(ctx, coreType, url)
+	})
+}
+
+func (c *interceptedClient) Exec(command string, buffer *bytes.Buffer) error {
+	return c.intercept(context.Background(), "Exec", func(ctx context.Context) error {
+		return c.next.ExecContext(ctx, command, buffer)
+	})
+}
+
+func (c *interceptedClient) ExecContext(
+	ctx context.Context,
+	command string,
+	buffer *bytes.Buffer,
+) error {
+	return c.intercept(ctx, "ExecContext", func(ctx context.Context) error {
+		return c.next.ExecContext(ctx, command, buffer)
+	})
+}
+
+func (c *interceptedClient) ExecEval(command string, buffer *bytes.Buffer) error {
+	return c.intercept(context.Background(), "ExecEval", func(ctx context.Context) error {
+		return c.next.ExecEvalContext(ctx, command, buffer)
+	})
+}
+
+func (c *interceptedClient) ExecEvalContext(
+	ctx context.Context,
+	command string,
+	buffer *bytes.Buffer,
+) error {
+	return c.intercept(ctx, "ExecEvalContext", func(ctx context.Context) error {
+		return c.next.ExecEvalContext(ctx, command, buffer)
+	})
+}
+
+func (c *interceptedClient) ExportConfig() (result *Config, err error) {
+	err = c.intercept(context.Background(), "ExportConfig", func(ctx context.Context) error {
+		result, err = c.next.ExportConfigContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) ExportConfigContext(ctx context.Context) (result *Config, err error) {
+	err = c.intercept(ctx, "ExportConfigContext", func(ctx context.Context) error {
+		result, err = c.next.ExportConfigContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) Finish(slot int) error {
+	return c.intercept(context.Background(), "Finish", func(ctx context.Context) error {
+		return c.next.Finish(slot)
+	})
+}
+
+func (c *interceptedClient) FinishAll() error {
+	return c.intercept(context.Background(), "FinishAll", func(ctx context.Context) error {
+		return c.next.FinishAllContext(ctx)
+	})
+}
+
+func (c *interceptedClient) FinishAllContext(ctx context.Context) error {
+	return c.intercept(ctx, "FinishAllContext", func(ctx context.Context) error {
+		return c.next.FinishAllContext(ctx)
+	})
+}
+
+func (c *interceptedClient) FinishSlot(slot int) error {
+	return c.intercept(context.Background(), "FinishSlot", func(ctx context.Context) error {
+		return c.next.FinishSlotContext(ctx, slot)
+	})
+}
+
+func (c *interceptedClient) FinishSlotContext(ctx context.Context, slot int) error {
+	return c.intercept(ctx, "FinishSlotContext", func(ctx context.Context) error {
+		return c.next.FinishSlotContext(ctx, slot)
+	})
+}
+
+func (c *interceptedClient) FinishSlotVerified(slot int, timeout time.Duration) error {
+	return c.intercept(context.Background(), "FinishSlotVerified", func(ctx context.Context) error {
+		return c.next.FinishSlotVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+func (c *interceptedClient) FinishSlotVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	return c.intercept(ctx, "FinishSlotVerifiedContext", func(ctx context.Context) error {
+		return c.next.FinishSlotVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+func (c *interceptedClient) Help() (result string, err error) {
+	err = c.intercept(context.Background(), "Help", func(ctx context.Context) error {
+		result, err = c.next.HelpContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) HelpContext(ctx context.Context) (result string, err error) {
+	err = c.intercept(ctx, "HelpContext", func(ctx context.Context) error {
+		result, err = c.next.HelpContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) ImportConfig(config *Config, dryRun bool) (result *Diff, err error) {
+	err = c.intercept(context.Background(), "ImportConfig", func(ctx context.Context) error {
+		result, err = c.next.ImportConfigContext(ctx, config, dryRun)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) ImportConfigContext(
+	ctx context.Context,
+	config *Config,
+	dryRun bool,
+) (result *Diff, err error) {
+	err = c.intercept(ctx, "ImportConfigContext", func(ctx context.Context) error {
+		result, err = c.next.ImportConfigContext(ctx, config, dryRun)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) Info() (result [][]interface{}, err error) {
+	err = c.intercept(context.Background(), "Info", func(ctx context.Context) error {
+		result, err = c.next.InfoContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) InfoContext(ctx context.Context) (result [][]interface{}, err error) {
+	err = c.intercept(ctx, "InfoContext", func(ctx context.Context) error {
+		result, err = c.next.InfoContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) InfoStruct(dst *Info) error {
+	return c.intercept(context.Background(), "InfoStruct", func(ctx context.Context) error {
+		return c.next.InfoStructContext(ctx, dst)
+	})
+}
+
+func (c *interceptedClient) InfoStructContext(ctx context.Context, dst *Info) error {
+	return c.intercept(ctx, "InfoStructContext", func(ctx context.Context) error {
+		return c.next.InfoStructContext(ctx, dst)
+	})
+}
+
+func (c *interceptedClient) LogUpdates(arg LogUpdatesArg) (result string, err error) {
+	err = c.intercept(context.Background(), "LogUpdates", func(ctx context.Context) error {
+		result, err = c.next.LogUpdatesContext(ctx, arg)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) LogUpdatesContext(
+	ctx context.Context,
+	arg LogUpdatesArg,
+) (result string, err error) {
+	err = c.intercept(ctx, "LogUpdatesContext", func(ctx context.Context) error {
+		result, err = c.next.LogUpdatesContext(ctx, arg)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) NumSlots() (result int, err error) {
+	err = c.intercept(context.Background(), "NumSlots", func(ctx context.Context) error {
+		result, err = c.next.NumSlotsContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) NumSlotsContext(ctx context.Context) (result int, err error) {
+	err = c.intercept(ctx, "NumSlotsContext", func(ctx context.Context) error {
+		result, err = c.next.NumSlotsContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) OnIdle(slot int) error {
+	return c.intercept(context.Background(), "OnIdle", func(ctx context.Context) error {
+		return c.next.OnIdleContext(ctx, slot)
+	})
+}
+
+func (c *interceptedClient) OnIdleAll() error {
+	return c.intercept(context.Background(), "OnIdleAll", func(ctx context.Context) error {
+		return c.next.OnIdleAllContext(ctx)
+	})
+}
+
+func (c *interceptedClient) OnIdleAllContext(ctx context.Context) error {
+	return c.intercept(ctx, "OnIdleAllContext", func(ctx context.Context) error {
+		return c.next.OnIdleAllContext(ctx)
+	})
+}
+
+func (c *interceptedClient) OnIdleContext(ctx context.Context, slot int) error {
+	return c.intercept(ctx, "OnIdleContext", func(ctx context.Context) error {
+		return c.next.OnIdleContext(ctx, slot)
+	})
+}
+
+func (c *interceptedClient) OnIdleVerified(slot int, timeout time.Duration) error {
+	return c.intercept(context.Background(), "OnIdleVerified", func(ctx context.Context) error {
+		return c.next.OnIdleVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+func (c *interceptedClient) OnIdleVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	return c.intercept(ctx, "OnIdleVerifiedContext", func(ctx context.Context) error {
+		return c.next.OnIdleVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+func (c *interceptedClient) OptionsApply(patch *OptionsPatch, dst *Options) error {
+	return c.intercept(context.Background(), "OptionsApply", func(ctx context.Context) error {
+		return c.next.OptionsApplyContext(ctx, patch, dst)
+	})
+}
+
+func (c *interceptedClient) OptionsApplyContext(
+	ctx context.Context,
+	patch *OptionsPatch,
+	dst *Options,
+) error {
+	return c.intercept(ctx, "OptionsApplyContext", func(ctx context.Context) error {
+		return c.next.OptionsApplyContext(ctx, patch, dst)
+	})
+}
+
+func (c *interceptedClient) OptionsChanged() (result map[string]string, err error) {
+	err = c.intercept(context.Background(), "OptionsChanged", func(ctx context.Context) error {
+		result, err = c.next.OptionsChangedContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) OptionsChangedContext(
+	ctx context.Context,
+) (result map[string]string, err error) {
+	err = c.intercept(ctx, "OptionsChangedContext", func(ctx context.Context) error {
+		result, err = c.next.OptionsChangedContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) OptionsGet(dst *Options) error {
+	return c.intercept(context.Background(), "OptionsGet", func(ctx context.Context) error {
+		return c.next.OptionsGetContext(ctx, dst)
+	})
+}
+
+func (c *interceptedClient) OptionsGetContext(ctx context.Context, dst *Options) error {
+	return c.intercept(ctx, "OptionsGetContext", func(ctx context.Context) error {
+		return c.next.OptionsGetContext(ctx, dst)
+	})
+}
+
+func (c *interceptedClient) OptionsReset(dst *Options, keys ...string) error {
+	return c.intercept(context.Background(), "OptionsReset", func(ctx context.Context) error {
+		return c.next.OptionsResetContext(ctx, dst, keys...)
+	})
+}
+
+func (c *interceptedClient) OptionsResetContext(
+	ctx context.Context,
+	dst *Options,
+	keys ...string,
+) error {
+	return c.intercept(ctx, "OptionsResetContext", func(ctx context.Context) error {
+		return c.next.OptionsResetContext(ctx, dst, keys...)
+	})
+}
+
+func (c *interceptedClient) OptionsSet(key string, value interface{}) error {
+	return c.intercept(context.Background(), "OptionsSet", func(ctx context.Context) error {
+		return c.next.OptionsSetContext(ctx, key, value)
+	})
+}
+
+func (c *interceptedClient) OptionsSetContext(
+	ctx context.Context,
+	key string,
+	value interface{},
+) error {
+	return c.intercept(ctx, "OptionsSetContext", func(ctx context.Context) error {
+		return c.next.OptionsSetContext(ctx, key, value)
+	})
+}
+
+func (c *interceptedClient) OptionsUpdate(changes OptionChanges, dst *Options) error {
+	return c.intercept(context.Background(), "OptionsUpdate", func(ctx context.Context) error {
+		return c.next.OptionsUpdateContext(ctx, changes, dst)
+	})
+}
+
+func (c *interceptedClient) OptionsUpdateContext(
+	ctx context.Context,
+	changes OptionChanges,
+	dst *Options,
+) error {
+	return c.intercept(ctx, "OptionsUpdateContext", func(ctx context.Context) error {
+		return c.next.OptionsUpdateContext(ctx, changes, dst)
+	})
+}
+
+func (c *interceptedClient) OptionsWithDefaults() (result map[string]string, err error) {
+	err = c.intercept(context.Background(), "OptionsWithDefaults", func(ctx context.Context) error {
+		result, err = c.next.OptionsWithDefaultsContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) OptionsWithDefaultsContext(
+	ctx context.Context,
+) (result map[string]string, err error) {
+	err = c.intercept(ctx, "OptionsWithDefaultsContext", func(ctx context.Context) error {
+		result, err = c.next.OptionsWithDefaultsContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) PPD() (result float64, err error) {
+	err = c.intercept(context.Background(), "PPD", func(ctx context.Context) error {
+		result, err = c.next.PPDContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) PPDContext(ctx context.Context) (result float64, err error) {
+	err = c.intercept(ctx, "PPDContext", func(ctx context.Context) error {
+		result, err = c.next.PPDContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) PauseAll() error {
+	return c.intercept(context.Background(), "PauseAll", func(ctx context.Context) error {
+		return c.next.PauseAllContext(ctx)
+	})
+}
+
+func (c *interceptedClient) PauseAllContext(ctx context.Context) error {
+	return c.intercept(ctx, "PauseAllContext", func(ctx context.Context) error {
+		return c.next.PauseAllContext(ctx)
+	})
+}
+
+func (c *interceptedClient) PauseSlot(slot int) error {
+	return c.intercept(context.Background(), "PauseSlot", func(ctx context.Context) error {
+		return c.next.PauseSlotContext(ctx, slot)
+	})
+}
+
+func (c *interceptedClient) PauseSlotContext(ctx context.Context, slot int) error {
+	return c.intercept(ctx, "PauseSlotContext", func(ctx context.Context) error {
+		return c.next.PauseSlotContext(ctx, slot)
+	})
+}
+
+func (c *interceptedClient) PauseSlotVerified(slot int, timeout time.Duration) error {
+	return c.intercept(context.Background(), "PauseSlotVerified", func(ctx context.Context) error {
+		return c.next.PauseSlotVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+func (c *interceptedClient) PauseSlotVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	return c.intercept(ctx, "PauseSlotVerifiedContext", func(ctx context.Context) error {
+		return c.next.PauseSlotVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+func (c *interceptedClient) QueueInfo() (result []SlotQueueInfo, err error) {
+	err = c.intercept(context.Background(), "QueueInfo", func(ctx context.Context) error {
+		result, err = c.next.QueueInfoContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) QueueInfoContext(
+	ctx context.Context,
+) (result []SlotQueueInfo, err error) {
+	err = c.intercept(ctx, "QueueInfoContext", func(ctx context.Context) error {
+		result, err = c.next.QueueInfoContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) Reconcile(config *Config, dryRun bool) (result *Diff, err error) {
+	err = c.intercept(context.Background(), "Reconcile", func(ctx context.Context) error {
+		result, err = c.next.ReconcileContext(ctx, config, dryRun)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) ReconcileContext(
+	ctx context.Context,
+	config *Config,
+	dryRun bool,
+) (result *Diff, err error) {
+	err = c.intercept(ctx, "ReconcileContext", func(ctx context.Context) error {
+		result, err = c.next.ReconcileContext(ctx, config, dryRun)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) RequestID() error {
+	return c.intercept(context.Background(), "RequestID", func(ctx context.Context) error {
+		return c.next.RequestIDContext(ctx)
+	})
+}
+
+func (c *interceptedClient) RequestIDContext(ctx context.Context) error {
+	return c.intercept(ctx, "RequestIDContext", func(ctx context.Context) error {
+		return c.next.RequestIDContext(ctx)
+	})
+}
+
+func (c *interceptedClient) RequestWS() error {
+	return c.intercept(context.Background(), "RequestWS", func(ctx context.Context) error {
+		return c.next.RequestWSContext(ctx)
+	})
+}
+
+func (c *interceptedClient) RequestWSContext(ctx context.Context) error {
+	return c.intercept(ctx, "RequestWSContext", func(ctx context.Context) error {
+		return c.next.RequestWSContext(ctx)
+	})
+}
+
+func (c *interceptedClient) Save(path string) error {
+	return c.intercept(context.Background(), "Save", func(ctx context.Context) error {
+		return c.next.SaveContext(ctx, path)
+	})
+}
+
+func (c *interceptedClient) SaveContext(ctx context.Context, path string) error {
+	return c.intercept(ctx, "SaveContext", func(ctx context.Context) error {
+		return c.next.SaveContext(ctx, path)
+	})
+}
+
+func (c *interceptedClient) Screensaver() error {
+	return c.intercept(context.Background(), "Screensaver", func(ctx context.Context) error {
+		return c.next.ScreensaverContext(ctx)
+	})
+}
+
+func (c *interceptedClient) ScreensaverContext(ctx context.Context) error {
+	return c.intercept(ctx, "ScreensaverContext", func(ctx context.Context) error {
+		return c.next.ScreensaverContext(ctx)
+	})
+}
+
+func (c *interceptedClient) Shutdown() error {
+	return c.intercept(context.Background(), "Shutdown", func(ctx context.Context) error {
+		return c.next.ShutdownContext(ctx)
+	})
+}
+
+func (c *interceptedClient) ShutdownContext(ctx context.Context) error {
+	return c.intercept(ctx, "ShutdownContext", func(ctx context.Context) error {
+		return c.next.ShutdownContext(ctx)
+	})
+}
+
+func (c *interceptedClient) SimulationInfo(slot int, dst *SimulationInfo) error {
+	return c.intercept(context.Background(), "SimulationInfo", func(ctx context.Context) error {
+		return c.next.SimulationInfoContext(ctx, slot, dst)
+	})
+}
+
+func (c *interceptedClient) SimulationInfoContext(
+	ctx context.Context,
+	slot int,
+	dst *SimulationInfo,
+) error {
+	return c.intercept(ctx, "SimulationInfoContext", func(ctx context.Context) error {
+		return c.next.SimulationInfoContext(ctx, slot, dst)
+	})
+}
+
+func (c *interceptedClient) SlotAdd(
+	slotType SlotType,
+	changes SlotChanges,
+) (result int, err error) {
+	err = c.intercept(context.Background(), "SlotAdd", func(ctx context.Context) error {
+		result, err = c.next.SlotAddContext(ctx, slotType, changes)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) SlotAddContext(
+	ctx context.Context,
+	slotType SlotType,
+	changes SlotChanges,
+) (result int, err error) {
+	err = c.intercept(ctx, "SlotAddContext", func(ctx context.Context) error {
+		result, err = c.next.SlotAddContext(ctx, slotType, changes)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) SlotDelete(slot int) error {
+	return c.intercept(context.Background(), "SlotDelete", func(ctx context.Context) error {
+		return c.next.SlotDeleteContext(ctx, slot)
+	})
+}
+
+func (c *interceptedClient) SlotDeleteContext(ctx context.Context, slot int) error {
+	return c.intercept(ctx, "SlotDeleteContext", func(ctx context.Context) error {
+		return c.next.SlotDeleteContext(ctx, slot)
+	})
+}
+
+func (c *interceptedClient) SlotDeleteVerified(slot int, timeout time.Duration) error {
+	return c.intercept(context.Background(), "SlotDeleteVerified", func(ctx context.Context) error {
+		return c.next.SlotDeleteVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+func (c *interceptedClient) SlotDeleteVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	return c.intercept(ctx, "SlotDeleteVerifiedContext", func(ctx context.Context) error {
+		return c.next.SlotDeleteVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+func (c *interceptedClient) SlotInfo() (result []SlotInfo, err error) {
+	err = c.intercept(context.Background(), "SlotInfo", func(ctx context.Context) error {
+		result, err = c.next.SlotInfoContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) SlotInfoContext(ctx context.Context) (result []SlotInfo, err error) {
+	err = c.intercept(ctx, "SlotInfoContext", func(ctx context.Context) error {
+		result, err = c.next.SlotInfoContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) SlotModify(slot int, slotType SlotType, changes SlotChanges) error {
+	return c.intercept(context.Background(), "SlotModify", func(ctx context.Context) error {
+		return c.next.SlotModifyContext(ctx, slot, slotType, changes)
+	})
+}
+
+func (c *interceptedClient) SlotModifyContext(
+	ctx context.Context,
+	slot int,
+	slotType SlotType,
+	changes SlotChanges,
+) error {
+	return c.intercept(ctx, "SlotModifyContext", func(ctx context.Context) error {
+		return c.next.SlotModifyContext(ctx, slot, slotType, changes)
+	})
+}
+
+func (c *interceptedClient) SlotOptionsGet(slot int, dst *SlotOptions) error {
+	return c.intercept(context.Background(), "SlotOptionsGet", func(ctx context.Context) error {
+		return c.next.SlotOptionsGetContext(ctx, slot, dst)
+	})
+}
+
+func (c *interceptedClient) SlotOptionsGetContext(
+	ctx context.Context,
+	slot int,
+	dst *SlotOptions,
+) error {
+	return c.intercept(ctx, "SlotOptionsGetContext", func(ctx context.Context) error {
+		return c.next.SlotOptionsGetContext(ctx, slot, dst)
+	})
+}
+
+func (c *interceptedClient) SlotOptionsReset(slot int, keys ...string) error {
+	return c.intercept(context.Background(), "SlotOptionsReset", func(ctx context.Context) error {
+		return c.next.SlotOptionsResetContext(ctx, slot, keys...)
+	})
+}
+
+func (c *interceptedClient) SlotOptionsResetContext(
+	ctx context.Context,
+	slot int,
+	keys ...string,
+) error {
+	return c.intercept(ctx, "SlotOptionsResetContext", func(ctx context.Context) error {
+		return c.next.SlotOptionsResetContext(ctx, slot, keys...)
+	})
+}
+
+func (c *interceptedClient) SlotOptionsSet(slot int, key string, value interface{}) error {
+	return c.intercept(context.Background(), "SlotOptionsSet", func(ctx context.Context) error {
+		return c.next.SlotOptionsSetContext(ctx, slot, key, value)
+	})
+}
+
+func (c *interceptedClient) SlotOptionsSetContext(
+	ctx context.Context,
+	slot int,
+	key string,
+	value interface{},
+) error {
+	return c.intercept(ctx, "SlotOptionsSetContext", func(ctx context.Context) error {
+		return c.next.SlotOptionsSetContext(ctx, slot, key, value)
+	})
+}
+
+func (c *interceptedClient) SlotOptionsUpdate(slot int, changes SlotChanges) error {
+	return c.intercept(context.Background(), "SlotOptionsUpdate", func(ctx context.Context) error {
+		return c.next.SlotOptionsUpdateContext(ctx, slot, changes)
+	})
+}
+
+func (c *interceptedClient) SlotOptionsUpdateContext(
+	ctx context.Context,
+	slot int,
+	changes SlotChanges,
+) error {
+	return c.intercept(ctx, "SlotOptionsUpdateContext", func(ctx context.Context) error {
+		return c.next.SlotOptionsUpdateContext(ctx, slot, changes)
+	})
+}
+
+func (c *interceptedClient) SubscribeLog(ctx context.Context) (result <-chan LogLine, err error) {
+	err = c.intercept(ctx, "SubscribeLog", func(context.Context) error {
+		result, err = c.next.SubscribeLog(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) UnpauseAll() error {
+	return c.intercept(context.Background(), "UnpauseAll", func(ctx context.Context) error {
+		return c.next.UnpauseAllContext(ctx)
+	})
+}
+
+func (c *interceptedClient) UnpauseAllContext(ctx context.Context) error {
+	return c.intercept(ctx, "UnpauseAllContext", func(ctx context.Context) error {
+		return c.next.UnpauseAllContext(ctx)
+	})
+}
+
+func (c *interceptedClient) UnpauseSlot(slot int) error {
+	return c.intercept(context.Background(), "UnpauseSlot", func(ctx context.Context) error {
+		return c.next.UnpauseSlotContext(ctx, slot)
+	})
+}
+
+func (c *interceptedClient) UnpauseSlotContext(ctx context.Context, slot int) error {
+	return c.intercept(ctx, "UnpauseSlotContext", func(ctx context.Context) error {
+		return c.next.UnpauseSlotContext(ctx, slot)
+	})
+}
+
+func (c *interceptedClient) UnpauseSlotVerified(slot int, timeout time.Duration) error {
+	return c.intercept(context.Background(), "UnpauseSlotVerified", func(ctx context.Context) error {
+		return c.next.UnpauseSlotVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+func (c *interceptedClient) UnpauseSlotVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	return c.intercept(ctx, "UnpauseSlotVerifiedContext", func(ctx context.Context) error {
+		return c.next.UnpauseSlotVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+func (c *interceptedClient) UpdatesAdd(id int, rate time.Duration, expression string) error {
+	return c.intercept(context.Background(), "UpdatesAdd", func(ctx context.Context) error {
+		return c.next.UpdatesAddContext(ctx, id, rate, expression)
+	})
+}
+
+func (c *interceptedClient) UpdatesAddContext(
+	ctx context.Context,
+	id int,
+	rate time.Duration,
+	expression string,
+) error {
+	return c.intercept(ctx, "UpdatesAddContext", func(ctx context.Context) error {
+		return c.next.UpdatesAddContext(ctx, id, rate, expression)
+	})
+}
+
+func (c *interceptedClient) UpdatesClear() error {
+	return c.intercept(context.Background(), "UpdatesClear", func(ctx context.Context) error {
+		return c.next.UpdatesClearContext(ctx)
+	})
+}
+
+func (c *interceptedClient) UpdatesClearContext(ctx context.Context) error {
+	return c.intercept(ctx, "UpdatesClearContext", func(ctx context.Context) error {
+		return c.next.UpdatesClearContext(ctx)
+	})
+}
+
+func (c *interceptedClient) UpdatesDel(id int) error {
+	return c.intercept(context.Background(), "UpdatesDel", func(ctx context.Context) error {
+		return c.next.UpdatesDelContext(ctx, id)
+	})
+}
+
+func (c *interceptedClient) UpdatesDelContext(ctx context.Context, id int) error {
+	return c.intercept(ctx, "UpdatesDelContext", func(ctx context.Context) error {
+		return c.next.UpdatesDelContext(ctx, id)
+	})
+}
+
+func (c *interceptedClient) UpdatesList() (result string, err error) {
+	err = c.intercept(context.Background(), "UpdatesList", func(ctx context.Context) error {
+		result, err = c.next.UpdatesListContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) UpdatesListContext(ctx context.Context) (result string, err error) {
+	err = c.intercept(ctx, "UpdatesListContext", func(ctx context.Context) error {
+		result, err = c.next.UpdatesListContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) UpdatesReset() error {
+	return c.intercept(context.Background(), "UpdatesReset", func(ctx context.Context) error {
+		return c.next.UpdatesResetContext(ctx)
+	})
+}
+
+func (c *interceptedClient) UpdatesResetContext(ctx context.Context) error {
+	return c.intercept(ctx, "UpdatesResetContext", func(ctx context.Context) error {
+		return c.next.UpdatesResetContext(ctx)
+	})
+}
+
+func (c *interceptedClient) Uptime() (result FAHDuration, err error) {
+	err = c.intercept(context.Background(), "Uptime", func(ctx context.Context) error {
+		result, err = c.next.UptimeContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) UptimeContext(ctx context.Context) (result FAHDuration, err error) {
+	err = c.intercept(ctx, "UptimeContext", func(ctx context.Context) error {
+		result, err = c.next.UptimeContext(ctx)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) WaitForUnits() error {
+	return c.intercept(context.Background(), "WaitForUnits", func(ctx context.Context) error {
+		return c.next.WaitForUnitsContext(ctx)
+	})
+}
+
+func (c *interceptedClient) WaitForUnitsContext(ctx context.Context) error {
+	return c.intercept(ctx, "WaitForUnitsContext", func(ctx context.Context) error {
+		return c.next.WaitForUnitsContext(ctx)
+	})
+}
+
+func (c *interceptedClient) WatchEvents(
+	ctx context.Context,
+	rate time.Duration,
+) (result <-chan WatchEvent, err error) {
+	err = c.intercept(ctx, "WatchEvents", func(context.Context) error {
+		result, err = c.next.WatchEvents(ctx, rate)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) WatchOptions(
+	ctx context.Context,
+	rate time.Duration,
+) (result <-chan Options, err error) {
+	err = c.intercept(ctx, "WatchOptions", func(context.Context) error {
+		result, err = c.next.WatchOptions(ctx, rate)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) WatchQueueInfo(
+	ctx context.Context,
+	rate time.Duration,
+) (result <-chan []SlotQueueInfo, err error) {
+	err = c.intercept(ctx, "WatchQueueInfo", func(context.Context) error {
+		result, err = c.next.WatchQueueInfo(ctx, rate)
+		return err
+	})
+	return
+}
+
+func (c *interceptedClient) WatchSlotInfo(
+	ctx context.Context,
+	rate time.Duration,
+) (result <-chan []SlotInfo, err error) {
+	err = c.intercept(ctx, "WatchSlotInfo", func(context.Context) error {
+		result, err = c.next.WatchSlotInfo(ctx, rate)
+		return err
+	})
+	return
+}
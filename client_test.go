@@ -0,0 +1,146 @@
+package fahapi_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/MakotoE/go-fahapi"
+	"github.com/MakotoE/go-fahapi/fahtest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestMockClient(t *testing.T) {
+	mock := &fahtest.MockClient{
+		NumSlotsFunc: func() (int, error) {
+			return 2, nil
+		},
+	}
+
+	n, err := mock.NumSlots()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+
+	_, err = mock.SlotInfo()
+	assert.True(t, errors.Is(err, fahtest.ErrNotMocked), err)
+	assert.Equal(t, []string{"NumSlots", "SlotInfo"}, mock.Calls())
+}
+
+func TestIntercept(t *testing.T) {
+	mock := &fahtest.MockClient{
+		NumSlotsContextFunc: func(ctx context.Context) (int, error) {
+			_, ok := ctx.Deadline()
+			assert.True(t, ok)
+			return 2, nil
+		},
+		PauseSlotContextFunc: func(ctx context.Context, slot int) error {
+			_, ok := ctx.Deadline()
+			assert.True(t, ok)
+			return errors.New("a")
+		},
+		WatchSlotInfoFunc: func(
+			ctx context.Context,
+			rate time.Duration,
+		) (<-chan []fahapi.SlotInfo, error) {
+			_, ok := ctx.Deadline()
+			assert.False(t, ok)
+			return nil, nil
+		},
+	}
+
+	var methods []string
+	client := fahapi.Intercept(mock, func(
+		ctx context.Context,
+		method string,
+		call func(ctx context.Context) error,
+	) error {
+		methods = append(methods, method)
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		return call(ctx)
+	})
+
+	n, err := client.NumSlotsContext(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.EqualError(t, client.PauseSlot(1), "a")
+	_, err = client.WatchSlotInfo(context.Background(), time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"NumSlotsContext", "PauseSlot", "WatchSlotInfo"}, methods)
+}
+
+func TestLoggingClient(t *testing.T) {
+	mock := &fahtest.MockClient{
+		PauseAllContextFunc: func(ctx context.Context) error {
+			return nil
+		},
+	}
+
+	buffer := &bytes.Buffer{}
+	client := fahapi.LoggingClient(mock, log.New(buffer, "", 0))
+	assert.Nil(t, client.PauseAll())
+	assert.NotNil(t, client.UnpauseAll())
+	assert.Regexp(
+		t,
+		`^PauseAll \(.+\)\nUnpauseAll \(.+\): fahtest: UnpauseAllContext: not mocked\n$`,
+		buffer.String(),
+	)
+}
+
+func TestMetricsClient(t *testing.T) {
+	mock := &fahtest.MockClient{
+		PPDContextFunc: func(ctx context.Context) (float64, error) {
+			return 1, nil
+		},
+	}
+
+	var observed []string
+	client := fahapi.MetricsClient(mock, func(method string, duration time.Duration, err error) {
+		assert.Nil(t, err)
+		assert.True(t, duration >= 0)
+		observed = append(observed, method)
+	})
+
+	ppd, err := client.PPD()
+	assert.Nil(t, err)
+	assert.Equal(t, 1.0, ppd)
+	assert.Equal(t, []string{"PPD"}, observed)
+}
+
+func TestRetryClient(t *testing.T) {
+	tests := []struct {
+		errs     []error
+		expected int // Number of calls
+	}{
+		{[]error{nil}, 1},
+		{[]error{errors.New("a")}, 1},
+		{[]error{&fahapi.DisconnectError{Reconnected: true}, nil}, 2},
+		{[]error{&fahapi.DisconnectError{Reconnected: true, Sent: true}}, 1},
+		{[]error{&fahapi.DisconnectError{}}, 1},
+		{
+			[]error{
+				&fahapi.DisconnectError{Reconnected: true},
+				&fahapi.DisconnectError{Reconnected: true},
+				&fahapi.DisconnectError{Reconnected: true},
+			},
+			3,
+		},
+	}
+
+	for i, test := range tests {
+		calls := 0
+		mock := &fahtest.MockClient{
+			UptimeContextFunc: func(ctx context.Context) (fahapi.FAHDuration, error) {
+				calls++
+				return fahapi.FAHDuration(calls), test.errs[calls-1]
+			},
+		}
+
+		uptime, err := fahapi.RetryClient(mock, 3).Uptime()
+		assert.Equal(t, test.expected, calls, i)
+		assert.Equal(t, fahapi.FAHDuration(calls), uptime, i)
+		assert.Equal(t, test.errs[calls-1], err, i)
+	}
+}
@@ -1,44 +1,251 @@
 package fahapi
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"github.com/pkg/errors"
 	"io"
 	"net"
 	"strings"
+	"sync"
+	"time"
 )
 
-// Connection holds the TCP connection to the FAH client. None of its methods are goroutine-safe.
+// Connection holds the connection to the FAH client. None of its methods are goroutine-safe, except
+// Close(), unless the connection is pipelined with WithPipelining().
 type Connection struct {
-	*net.TCPConn
-	Addr net.TCPAddr // Reconnects to this address on disconnection.
+	net.Conn
+	// Addr is the TCP address of the client, or zero if the connection is not TCP. If it is
+	// changed, the connection reconnects to the new address on disconnection.
+	Addr net.TCPAddr
+
+	addr     string // Passed to dial
+	addrDial string // Addr.String() after the last connection, to tell if Addr was changed
+
+	// buffered reads Conn. Bytes that arrive after a message are kept for the next one.
+	buffered *bufio.Reader
+
+	dial            DialFunc
+	commandTimeout  time.Duration
+	reconnectPolicy ReconnectPolicy
+	onDisconnect    func(err error)
+	onReconnect     func()
+
+	// interrupted is set when a command was cancelled before its response was read. The next
+	// command reconnects before it is sent.
+	interrupted bool
+
+	// password is sent with the auth command after reconnecting. It is set by API.Auth() or
+	// WithPassword().
+	password string
+
+	pipelined      bool       // Set by WithPipelining()
+	reconnectMutex sync.Mutex // Makes one command reconnect a pipelined connection
+
+	mutex         sync.Mutex // Guards Conn changes, closed, reader, writer and subscriptions
+	closed        bool       // Set by Close()
+	reader        *reader
+	writer        *writer // Set if pipelined
+	subscriptions map[string]map[*subscription]struct{}
+}
+
+// DialFunc connects to addr. The connection can be any stream to the client, like a TLS connection
+// or an SSH-forwarded socket.
+type DialFunc func(ctx context.Context, addr string) (net.Conn, error)
+
+// DialOption configures DialContext() and DialConnectionContext().
+type DialOption func(*Connection)
+
+// WithDialer makes the connection dial TCP with dialer.
+func WithDialer(dialer *net.Dialer) DialOption {
+	return func(c *Connection) {
+		c.dial = func(ctx context.Context, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "tcp", addr)
+		}
+	}
+}
+
+// WithDialFunc makes the connection use dial to connect and reconnect. addr is passed to dial as
+// is, so it does not have to be a network address.
+func WithDialFunc(dial DialFunc) DialOption {
+	return func(c *Connection) {
+		c.dial = dial
+	}
 }
 
+// WithCommandTimeout limits the time that each command can take, including reconnection. Zero
+// means no limit.
+func WithCommandTimeout(timeout time.Duration) DialOption {
+	return func(c *Connection) {
+		c.commandTimeout = timeout
+	}
+}
+
+// WithPassword authenticates the connection with password when it is established.
+func WithPassword(password string) DialOption {
+	return func(c *Connection) {
+		c.password = password
+	}
+}
+
+// DialConnection connects to addr over TCP.
 func DialConnection(addr *net.TCPAddr) (*Connection, error) {
-	conn, err := connect(addr)
-	if err != nil {
+	return DialConnectionContext(context.Background(), addr.String())
+}
+
+// DialConnectionContext connects to addr, which is a host:port pair unless WithDialFunc() is
+// given. ctx is only used while connecting.
+func DialConnectionContext(
+	ctx context.Context,
+	addr string,
+	options ...DialOption,
+) (*Connection, error) {
+	c := &Connection{
+		addr:          addr,
+		subscriptions: map[string]map[*subscription]struct{}{},
+	}
+
+	defaults := []DialOption{WithDialer(&net.Dialer{}), WithReconnectPolicy(DefaultReconnectPolicy)}
+	for _, option := range append(defaults, options...) {
+		option(c)
+	}
+
+	if err := c.establish(ctx); err != nil {
 		return nil, err
 	}
 
-	return &Connection{TCPConn: conn, Addr: *addr}, nil
+	return c, nil
 }
 
-func connect(addr *net.TCPAddr) (*net.TCPConn, error) {
-	conn, err := net.DialTCP("tcp", nil, addr)
+// establish connects and authenticates. The previous connection must be closed.
+func (c *Connection) establish(ctx context.Context) error {
+	conn, buffered, err := c.connect(ctx)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return err
 	}
 
-	if err = readMessage(conn, &bytes.Buffer{}); err != nil { // Discard welcome message
-		return nil, errors.WithStack(err)
+	c.mutex.Lock()
+	if c.closed {
+		c.mutex.Unlock()
+		conn.Close()
+		return errors.New("connection closed")
 	}
 
-	return conn, nil
+	c.Conn = conn
+	c.buffered = buffered
+	c.reader = startReader(c)
+	c.mutex.Unlock()
+
+	c.Addr = net.TCPAddr{}
+	if tcpAddr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		c.Addr = *tcpAddr
+	}
+	c.addrDial = c.Addr.String()
+
+	if c.password != "" {
+		if err := c.authenticate(ctx); err != nil {
+			c.stopReader(err)
+			c.Conn.Close()
+			return err
+		}
+	}
+
+	if c.pipelined {
+		// Commands from other goroutines are sent after authentication
+		c.mutex.Lock()
+		c.writer = startWriter(c)
+		c.mutex.Unlock()
+	}
+
+	return nil
+}
+
+func (c *Connection) connect(ctx context.Context) (net.Conn, *bufio.Reader, error) {
+	if addr := c.Addr.String(); c.addrDial != "" && addr != c.addrDial {
+		c.addr = addr
+	}
+
+	conn, err := c.dial(ctx, c.addr)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	buffered := bufio.NewReader(conn)
+
+	stop := interruptOnDone(ctx, conn)
+	// Discard welcome message
+	_, err = (&messageReader{r: buffered}).readMessage(&bytes.Buffer{})
+	if stop() {
+		conn.Close()
+		return nil, nil, errors.WithStack(ctx.Err())
+	}
+
+	if err != nil {
+		conn.Close()
+		return nil, nil, errors.WithStack(err)
+	}
+
+	return conn, buffered, nil
+}
+
+// aLongTimeAgo is a deadline in the past that makes blocked reads and writes return immediately.
+var aLongTimeAgo = time.Unix(1, 0)
+
+// interruptOnDone unblocks conn when ctx is done. The returned function must be called once the
+// operation on conn has finished; it returns true if conn was interrupted, in which case conn is in
+// an unknown state and should be closed.
+func interruptOnDone(ctx context.Context, conn net.Conn) func() bool {
+	if ctx.Done() == nil {
+		return func() bool { return false }
+	}
+
+	done := make(chan struct{})
+	interrupted := make(chan bool, 1)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.SetDeadline(aLongTimeAgo)
+			interrupted <- true
+		case <-done:
+			interrupted <- false
+		}
+	}()
+
+	return func() bool {
+		close(done)
+		return <-interrupted
+	}
+}
+
+// Close closes the connection. Unlike other methods, it can be called while a command is running.
+func (c *Connection) Close() error {
+	c.mutex.Lock()
+	c.closed = true
+	conn := c.Conn
+	c.mutex.Unlock()
+
+	c.stopReader(errors.New("connection closed"))
+	return errors.WithStack(conn.Close())
+}
+
+func (c *Connection) isClosed() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.closed
 }
 
 // Exec executes a command on the FAH client and writes the response to buffer.
 func (c *Connection) Exec(command string, buffer *bytes.Buffer) error {
+	return c.ExecContext(context.Background(), command, buffer)
+}
+
+// ExecContext is Exec with a context. If ctx is done before the response is read, ctx.Err() is
+// returned and the connection is reestablished before the next command is sent. If the connection
+// is lost, it is reestablished following the ReconnectPolicy and a *DisconnectError is returned.
+func (c *Connection) ExecContext(ctx context.Context, command string, buffer *bytes.Buffer) error {
 	if command == "" {
 		// FAH doesn't respond to an empty command
 		buffer.Reset()
@@ -49,63 +256,429 @@ func (c *Connection) Exec(command string, buffer *bytes.Buffer) error {
 		return errors.New("command contains newline")
 	}
 
-	if _, err := c.TCPConn.Write(append([]byte(command), '\n')); err != nil {
+	if c.isClosed() {
+		return errors.New("connection closed")
+	}
+
+	if c.commandTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.commandTimeout)
+		defer cancel()
+	}
+
+	if err := ctx.Err(); err != nil {
 		return errors.WithStack(err)
 	}
 
-	err := readMessage(c.TCPConn, buffer)
-	if errors.Cause(err) == io.EOF {
-		c.TCPConn.Close()
+	if c.pipelined {
+		return c.execPipelined(ctx, command, buffer)
+	}
+
+	if c.interrupted {
+		if err := c.reconnect(ctx); err != nil {
+			return &DisconnectError{Err: err}
+		}
+	}
+
+	sent, err := c.exec(ctx, command, buffer)
+	if err == nil {
+		return checkResponse(command, buffer.Bytes())
+	}
+
+	c.stopReader(err)
+	c.Conn.Close()
+	c.interrupted = true
+
+	if ctx.Err() != nil && errors.Cause(err) == ctx.Err() {
+		// The rest of the response is still in flight
+		return err
+	}
+
+	if c.onDisconnect != nil {
+		c.onDisconnect(err)
+	}
+
+	return &DisconnectError{Err: err, Sent: sent, Reconnected: c.reconnect(ctx) == nil}
+}
+
+// exec sends command and reads the response. sent is false if the command was not written.
+func (c *Connection) exec(
+	ctx context.Context,
+	command string,
+	buffer *bytes.Buffer,
+) (sent bool, err error) {
+	response := make(chan []byte, 1)
+	c.reader.expect(response)
+	if n, err := c.Conn.Write(append([]byte(command), '\n')); err != nil {
+		return n > 0, errors.WithStack(err)
+	}
+
+	b, err := c.reader.next(ctx, response)
+	buffer.Reset()
+	buffer.Write(b)
+	return true, err
+}
+
+// authenticate sends the saved password on a new connection.
+func (c *Connection) authenticate(ctx context.Context) error {
+	buffer := &bytes.Buffer{}
+	if _, err := c.exec(ctx, authCommand(c.password), buffer); err != nil {
+		return err
+	}
+
+	return checkAuthResponse(buffer.String())
+}
+
+// argEscaper escapes backslashes and quotes in a quoted command argument.
+var argEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// quoteArg returns s as one command argument. s is quoted if it is empty or contains spaces, quotes
+// or backslashes.
+func quoteArg(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\"\\") {
+		return s
+	}
+	return `"` + argEscaper.Replace(s) + `"`
+}
+
+// authCommand returns the auth command for password. The password is always quoted so that it can
+// contain spaces.
+func authCommand(password string) string {
+	return `auth "` + argEscaper.Replace(password) + `"`
+}
+
+// checkAuthResponse returns an error if the auth command failed. The client responds with "OK" or
+// nothing on success, and with an error message otherwise.
+func checkAuthResponse(response string) error {
+	response = strings.TrimSpace(response)
+	if response == "" || response == "OK" {
+		return nil
+	}
+
+	return errors.Errorf("authentication failed: %s", response)
+}
+
+// stopReader stops the reader goroutine and ends all subscriptions with err.
+func (c *Connection) stopReader(err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.reader.isStopped() {
+		c.reader.stop()
+		c.endSubscriptions(err)
+	}
+}
+
+// readerFailed ends all subscriptions with err, unless r was stopped by stopReader().
+func (c *Connection) readerFailed(r *reader, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !r.isStopped() {
+		r.stop()
+		c.endSubscriptions(err)
+	}
+}
+
+// reader reads messages from the connection in its own goroutine so that messages which the
+// client sends without a command are received while no command is running. Responses are matched
+// to commands in the order that the commands were sent.
+type reader struct {
+	mutex    sync.Mutex
+	waiting  []chan []byte // Response channels of the commands that were sent; guarded by mutex
+	stopped  chan struct{} // Closed to stop the goroutine
+	stopOnce sync.Once
+	done     chan struct{} // Closed when the goroutine has exited
+	err      error         // Set before done is closed
+}
+
+func startReader(c *Connection) *reader {
+	r := &reader{
+		stopped: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go r.run(c, c.buffered)
+	return r
+}
+
+func (r *reader) run(c *Connection, buffered *bufio.Reader) {
+	defer close(r.done)
+
+	m := &messageReader{
+		r:        buffered,
+		classify: c.classifyMessage,
+		pending:  r.pending,
+	}
 
-		conn, err := connect(&c.Addr)
+	for {
+		buffer := &bytes.Buffer{}
+		push, err := m.readMessage(buffer)
 		if err != nil {
-			return err
+			r.err = err
+			c.readerFailed(r, err)
+			return
+		}
+
+		if push {
+			c.dispatch(buffer.Bytes())
+			continue
 		}
 
-		c.TCPConn = conn
+		// The response of a cancelled command is discarded
+		if response := r.pop(); response != nil {
+			response <- buffer.Bytes()
+		}
 	}
-	return err
 }
 
+// expect must be called before a command is sent. The response is sent to response, which must
+// have a buffer.
+func (r *reader) expect(response chan []byte) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.waiting = append(r.waiting, response)
+}
+
+// pop removes the response channel of the oldest command, or returns nil.
+func (r *reader) pop() chan []byte {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.waiting) == 0 {
+		return nil
+	}
+
+	response := r.waiting[0]
+	r.waiting = r.waiting[1:]
+	return response
+}
+
+// pending returns true if a command is waiting for its response.
+func (r *reader) pending() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return len(r.waiting) > 0
+}
+
+// next returns the response that is sent to response.
+func (r *reader) next(ctx context.Context, response chan []byte) ([]byte, error) {
+	select {
+	case b := <-response:
+		return b, nil
+	case <-r.done:
+		select {
+		case b := <-response:
+			return b, nil
+		default:
+			return nil, r.err
+		}
+	case <-ctx.Done():
+		return nil, errors.WithStack(ctx.Err())
+	}
+}
+
+func (r *reader) stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopped)
+	})
+}
+
+func (r *reader) isStopped() bool {
+	select {
+	case <-r.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// messageKind tells messageReader how to read a PyON message.
+type messageKind int
+
+const (
+	// responseMessage is the response to a command, which ends with a prompt.
+	responseMessage messageKind = iota
+	// pushMessage is sent by the client without a command, like log-update.
+	pushMessage
+	// updateMessage is sent by the client for an update, but the response to a command has the
+	// same name. Responses are followed by a prompt.
+	updateMessage
+)
+
+// messageReader reads messages from the client.
+type messageReader struct {
+	r *bufio.Reader // Keeps bytes that were read ahead of the current message
+
+	// classify returns the kind of PyON message named name. If nil, all messages are responses.
+	classify func(name string) messageKind
+	// pending returns true if a command is waiting for its response. May be nil.
+	pending func() bool
+}
+
+// readMessage reads a response, which ends with a prompt, into buffer. It is for readers that do
+// not need to separate pushed messages. Bytes after the prompt might be read from r and discarded.
 func readMessage(r io.Reader, buffer *bytes.Buffer) error {
+	_, err := (&messageReader{r: bufio.NewReader(r)}).readMessage(buffer)
+	return err
+}
+
+// peek returns the buffered bytes, reading more if there are none.
+func (m *messageReader) peek() ([]byte, error) {
+	if m.r.Buffered() == 0 {
+		if _, err := m.r.Peek(1); err != nil {
+			if err == io.EOF {
+				return nil, errors.WithMessage(err, "the command might have been invalid")
+			}
+
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	return m.r.Peek(m.r.Buffered())
+}
+
+// readMessage reads a response into buffer. If the response starts with a PyON message that the
+// client pushed without a command, only that message is read and true is returned.
+func (m *messageReader) readMessage(buffer *bytes.Buffer) (bool, error) {
+	const endOfMessage = "\n> "
+	const endOfPyON = "\n---\n"
+
 	buffer.Reset()
+	headerRead := false
+	kind := responseMessage
 	for {
-		b := [1]byte{} // Read() blocks if there is no data to fill buffer completely
-		n, err := r.Read(b[:])
+		chunk, err := m.peek()
 		if err != nil {
-			if err == io.EOF {
-				return errors.WithMessage(err, "the command might have been invalid")
+			return false, err
+		}
+
+		searched := buffer.Len()
+		buffer.Write(chunk)
+		b := buffer.Bytes()
+
+		if !headerRead && len(b) > 1 {
+			if i := bytes.IndexByte(b[max(1, searched):], '\n'); i != -1 {
+				headerRead = true
+				if m.classify != nil {
+					kind = classifyHeader(b[:max(1, searched)+i+1], m.classify)
+				}
 			}
+		}
+
+		// end is the length of the message including the terminator
+		end := indexFrom(b, endOfMessage, searched-len(endOfMessage)+1)
+		if end != -1 {
+			end += len(endOfMessage)
+		}
 
-			return errors.WithStack(err)
+		isPyON := false
+		if kind != responseMessage {
+			i := indexFrom(b, endOfPyON, searched-len(endOfPyON)+1)
+			if i != -1 && (end == -1 || i+len(endOfPyON) < end) {
+				end = i + len(endOfPyON)
+				isPyON = true
+			}
 		}
-		if n <= 0 {
+
+		if end == -1 {
+			_, _ = m.r.Discard(len(chunk))
 			continue
 		}
 
-		_ = buffer.WriteByte(b[0])
+		_, _ = m.r.Discard(end - searched)
+		buffer.Truncate(end)
 
-		const endOfMessage = "\n> "
-		if buffer.Len() >= len(endOfMessage) &&
-			bytes.Equal(buffer.Bytes()[buffer.Len()-len(endOfMessage):], []byte(endOfMessage)) {
-			buffer.Truncate(buffer.Len() - len(endOfMessage))
-			if buffer.Len() > 0 && buffer.Bytes()[0] == '\n' {
+		if isPyON {
+			push := true
+			if kind == updateMessage && m.pending != nil && m.pending() {
+				if push, err = m.notFollowedByPrompt(); err != nil {
+					return false, err
+				}
+			}
+
+			buffer.Truncate(buffer.Len() - 1)
+			if buffer.Bytes()[0] == '\n' {
 				buffer.Next(1)
 			}
-			return nil
+			return push, nil
+		}
+
+		buffer.Truncate(buffer.Len() - len(endOfMessage))
+		if buffer.Len() > 0 && buffer.Bytes()[0] == '\n' {
+			buffer.Next(1)
 		}
+		return false, nil
 	}
 }
 
+// indexFrom returns the index of the first sep in b at or after start, or -1.
+func indexFrom(b []byte, sep string, start int) int {
+	start = max(0, start)
+	if i := bytes.Index(b[start:], []byte(sep)); i != -1 {
+		return start + i
+	}
+	return -1
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// notFollowedByPrompt checks if the message that was just read is followed by a prompt. The prompt
+// is consumed; other bytes are kept for the next message.
+func (m *messageReader) notFollowedByPrompt() (bool, error) {
+	const prompt = "> "
+
+	for n := 1; n <= len(prompt); n++ {
+		b, err := m.r.Peek(n)
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+
+		if b[n-1] != prompt[n-1] {
+			return true, nil
+		}
+	}
+
+	_, _ = m.r.Discard(len(prompt))
+	return false, nil
+}
+
+// classifyHeader returns the kind of message that starts with the PyON header in b.
+func classifyHeader(b []byte, classify func(name string) messageKind) messageKind {
+	header := bytes.TrimPrefix(b, []byte("\n"))
+	header = header[:bytes.IndexByte(header, '\n')]
+	fields := bytes.Fields(header)
+	if len(fields) != 3 || !bytes.Equal(fields[0], []byte("PyON")) {
+		return responseMessage
+	}
+	return classify(string(fields[2]))
+}
+
 // ExecEval executes commands which do not return a trailing newline.
 func (c *Connection) ExecEval(command string, buffer *bytes.Buffer) error {
+	return c.ExecEvalContext(context.Background(), command, buffer)
+}
+
+// ExecEvalContext is ExecEval with a context.
+func (c *Connection) ExecEvalContext(
+	ctx context.Context,
+	command string,
+	buffer *bytes.Buffer,
+) error {
 	if command == "" {
 		// FAH doesn't respond to an empty command
 		buffer.Reset()
 		return nil
 	}
 
-	if err := c.Exec(fmt.Sprintf(`eval "$(%s)\n"`, command), buffer); err != nil {
+	if err := c.ExecContext(ctx, fmt.Sprintf(`eval "$(%s)\n"`, command), buffer); err != nil {
 		return err
 	}
 
@@ -116,3 +689,126 @@ func (c *Connection) ExecEval(command string, buffer *bytes.Buffer) error {
 
 	return nil
 }
+
+// logUpdateName is the name of the PyON messages that contain new log lines.
+const logUpdateName = "log-update"
+
+func (c *Connection) classifyMessage(name string) messageKind {
+	if name == logUpdateName {
+		return pushMessage
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.subscriptions[name]) > 0 {
+		return updateMessage
+	}
+	return responseMessage
+}
+
+// subscription receives the PyON messages with a given name that the client sends without a
+// command. Messages are queued so that a slow subscriber does not block responses.
+type subscription struct {
+	name     string
+	mutex    sync.Mutex
+	messages [][]byte
+	err      error
+	ready    chan struct{} // Has a value if messages or err changed
+}
+
+// subscribe returns a subscription to PyON messages named name. Call unsubscribe() when done. The
+// subscription ends when the connection is lost.
+func (c *Connection) subscribe(name string) *subscription {
+	s := &subscription{name: name, ready: make(chan struct{}, 1)}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.subscriptions[name] == nil {
+		c.subscriptions[name] = map[*subscription]struct{}{}
+	}
+	c.subscriptions[name][s] = struct{}{}
+	return s
+}
+
+func (c *Connection) unsubscribe(s *subscription) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.subscriptions[s.name], s)
+	if len(c.subscriptions[s.name]) == 0 {
+		delete(c.subscriptions, s.name)
+	}
+}
+
+// dispatch sends a pushed message to its subscribers.
+func (c *Connection) dispatch(message []byte) {
+	name, _, _, err := splitPyONMessage(message)
+	if err != nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for s := range c.subscriptions[name] {
+		s.push(message)
+	}
+}
+
+// endSubscriptions must be called with c.mutex held.
+func (c *Connection) endSubscriptions(err error) {
+	for name, subscriptions := range c.subscriptions {
+		for s := range subscriptions {
+			s.end(err)
+		}
+		delete(c.subscriptions, name)
+	}
+}
+
+func (s *subscription) push(message []byte) {
+	s.mutex.Lock()
+	s.messages = append(s.messages, message)
+	s.mutex.Unlock()
+	s.signal()
+}
+
+func (s *subscription) end(err error) {
+	s.mutex.Lock()
+	s.err = err
+	s.mutex.Unlock()
+	s.signal()
+}
+
+func (s *subscription) signal() {
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// next returns the next message. It returns an error if ctx is done or the subscription ended.
+func (s *subscription) next(ctx context.Context) ([]byte, error) {
+	for {
+		s.mutex.Lock()
+		if len(s.messages) > 0 {
+			message := s.messages[0]
+			s.messages = s.messages[1:]
+			s.mutex.Unlock()
+			return message, nil
+		}
+
+		err := s.err
+		s.mutex.Unlock()
+		if err != nil {
+			return nil, err
+		}
+
+		select {
+		case <-s.ready:
+		case <-ctx.Done():
+			return nil, errors.WithStack(ctx.Err())
+		}
+	}
+}
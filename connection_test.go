@@ -1,12 +1,19 @@
 package fahapi
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"fmt"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"io"
+	"net"
 	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 )
 
 func TestReadMessage(t *testing.T) {
@@ -54,23 +61,315 @@ func TestReadMessage(t *testing.T) {
 
 	buffer := &bytes.Buffer{}
 	for i, test := range tests {
-		err := readMessage(strings.NewReader(test.s), buffer)
+		err := readMessage(iotest.OneByteReader(strings.NewReader(test.s)), buffer)
 		assert.Equal(t, test.expectedError, errors.Cause(err), i)
 		assert.Equal(t, test.expected, buffer.String(), i)
 	}
 }
 
+func TestMessageReader_readMessage(t *testing.T) {
+	const messages = "\nPyON 1 log-update\n\"a\"\n---\n" +
+		"\nPyON 1 units\n[]\n---\n" + // Update while no command is pending
+		"\nPyON 1 units\n[1]\n---\n" + // Update before the response
+		"\nPyON 1 units\n[2]\n---\n> " + // Response
+		"\nPyON 1 ppd\n1\n---\n> "
+
+	tests := []struct {
+		pending  bool
+		push     bool
+		expected string
+	}{
+		{false, true, "PyON 1 log-update\n\"a\"\n---"},
+		{false, true, "PyON 1 units\n[]\n---"},
+		{true, true, "PyON 1 units\n[1]\n---"},
+		{true, false, "PyON 1 units\n[2]\n---"},
+		{true, false, "PyON 1 ppd\n1\n---"},
+	}
+
+	// Messages can be split anywhere between reads
+	readers := []io.Reader{
+		strings.NewReader(messages),
+		iotest.OneByteReader(strings.NewReader(messages)),
+		iotest.HalfReader(strings.NewReader(messages)),
+	}
+
+	for _, r := range readers {
+		pending := false
+		m := &messageReader{
+			r: bufio.NewReader(r),
+			classify: func(name string) messageKind {
+				switch name {
+				case "log-update":
+					return pushMessage
+				case "units":
+					return updateMessage
+				}
+				return responseMessage
+			},
+			pending: func() bool {
+				return pending
+			},
+		}
+
+		buffer := &bytes.Buffer{}
+		for i, test := range tests {
+			pending = test.pending
+			push, err := m.readMessage(buffer)
+			assert.Nil(t, err, i)
+			assert.Equal(t, test.push, push, i)
+			assert.Equal(t, test.expected, buffer.String(), i)
+		}
+	}
+}
+
 func BenchmarkReadMessage(b *testing.B) {
-	// BenchmarkReadMessage-8   	 4450298	       268 ns/op
+	// BenchmarkReadMessage-8   	13012663	        89.0 ns/op
 	buffer := &bytes.Buffer{}
 	var result []byte
 	r := &bytes.Buffer{}
+	m := &messageReader{r: bufio.NewReader(r)}
 	for i := 0; i < b.N; i++ {
 		r.WriteString("message\n> ")
-		if err := readMessage(r, buffer); err != nil {
+		if _, err := m.readMessage(buffer); err != nil {
 			panic(err)
 		}
 		result = buffer.Bytes()
 	}
 	_ = result
 }
+
+// countingReader counts calls to Read(), which are syscalls for network connections.
+type countingReader struct {
+	r     io.Reader
+	reads int
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	c.reads++
+	return c.r.Read(b)
+}
+
+func BenchmarkMessageReader_log(b *testing.B) {
+	// One-byte reads:
+	// BenchmarkMessageReader_log-8   	     583	   2361025 ns/op	     63026 reads/op
+	// bufio.Reader:
+	// BenchmarkMessageReader_log-8   	  103670	     11530 ns/op	        16.0 reads/op
+	line := "12:00:00:WU00:FS00:0xa7:Completed 100 out of 1000 steps (10%)\\n"
+	message := []byte("\nPyON 1 log-update\n\"" + strings.Repeat(line, 1000) + "\"\n---\n")
+
+	buffer := &bytes.Buffer{}
+	r := &countingReader{}
+	b.SetBytes(int64(len(message)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.r = bytes.NewReader(message)
+		m := &messageReader{
+			r: bufio.NewReader(r),
+			classify: func(string) messageKind {
+				return pushMessage
+			},
+		}
+		if _, err := m.readMessage(buffer); err != nil {
+			panic(err)
+		}
+	}
+	b.ReportMetric(float64(r.reads)/float64(b.N), "reads/op")
+}
+
+// serveFake accepts connections on a local listener and writes the result of handler after each
+// command. The result should end with a prompt unless the connection is expected to hang. handler
+// may block to simulate a slow command. Close the returned listener when done.
+func serveFake(t *testing.T, handler func(command string) string) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				if _, err := conn.Write([]byte("Welcome\n> ")); err != nil {
+					return
+				}
+
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					if _, err := conn.Write([]byte(handler(scanner.Text()))); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	return listener
+}
+
+func TestConnection_ExecContext(t *testing.T) {
+	unblock := make(chan struct{})
+	defer close(unblock)
+
+	listener := serveFake(t, func(command string) string {
+		if command == "slow" {
+			<-unblock
+		}
+		return command + "\n> "
+	})
+	defer listener.Close()
+
+	conn, err := DialConnection(listener.Addr().(*net.TCPAddr))
+	require.Nil(t, err)
+	defer conn.Close()
+
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, conn.ExecContext(context.Background(), "a", buffer))
+	assert.Equal(t, "a", buffer.String())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, errors.Cause(conn.ExecContext(ctx, "slow", buffer)))
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, errors.Cause(conn.ExecContext(cancelled, "b", buffer)))
+
+	// Reconnects after the interrupted command
+	assert.Nil(t, conn.ExecContext(context.Background(), "c", buffer))
+	assert.Equal(t, "c", buffer.String())
+}
+
+func TestConnection_Addr(t *testing.T) {
+	unblock := make(chan struct{})
+	defer close(unblock)
+
+	listenerA := serveFake(t, func(command string) string {
+		if command == "slow" {
+			<-unblock
+		}
+		return "a\n> "
+	})
+	defer listenerA.Close()
+
+	listenerB := serveFake(t, func(command string) string {
+		return "b\n> "
+	})
+	defer listenerB.Close()
+
+	conn, err := DialConnection(listenerA.Addr().(*net.TCPAddr))
+	require.Nil(t, err)
+	defer conn.Close()
+	assert.Equal(t, listenerA.Addr().String(), conn.Addr.String())
+
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, conn.Exec("c", buffer))
+	assert.Equal(t, "a", buffer.String())
+
+	// Reconnects to the new address after the interrupted command
+	conn.Addr = *listenerB.Addr().(*net.TCPAddr)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	assert.NotNil(t, conn.ExecContext(ctx, "slow", buffer))
+
+	assert.Nil(t, conn.Exec("c", buffer))
+	assert.Equal(t, "b", buffer.String())
+	assert.Equal(t, listenerB.Addr().String(), conn.Addr.String())
+}
+
+func TestAPI_Auth(t *testing.T) {
+	commands := make(chan string, 10)
+	listener := serveFake(t, func(command string) string {
+		commands <- command
+		if strings.HasPrefix(command, "auth ") && command != `auth "a \"b\\"` {
+			return "\nERROR: invalid password\n> "
+		}
+		return "\nOK\n> "
+	})
+	defer listener.Close()
+
+	api, err := Dial(listener.Addr().(*net.TCPAddr))
+	require.Nil(t, err)
+	defer api.Close()
+
+	assert.NotNil(t, api.Auth("a"))
+	assert.Equal(t, `auth "a"`, <-commands)
+	assert.Equal(t, "", api.password)
+
+	require.Nil(t, api.Auth(`a "b\`))
+	assert.Equal(t, `auth "a \"b\\"`, <-commands)
+
+	api.interrupted = true
+	assert.Nil(t, api.Exec("ppd", &bytes.Buffer{}))
+	assert.Equal(t, `auth "a \"b\\"`, <-commands)
+	assert.Equal(t, "ppd", <-commands)
+}
+
+func TestDialConnectionContext_options(t *testing.T) {
+	commands := make(chan string, 10)
+	dials := 0
+	dial := func(ctx context.Context, addr string) (net.Conn, error) {
+		assert.Equal(t, "pipe", addr)
+		dials++
+
+		client, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			if _, err := server.Write([]byte("Welcome\n> ")); err != nil {
+				return
+			}
+
+			scanner := bufio.NewScanner(server)
+			for scanner.Scan() {
+				commands <- scanner.Text()
+				if scanner.Text() == "slow" {
+					continue // Never responds
+				}
+
+				if _, err := server.Write([]byte("\nOK\n> ")); err != nil {
+					return
+				}
+			}
+		}()
+		return client, nil
+	}
+
+	conn, err := DialConnectionContext(
+		context.Background(),
+		"pipe",
+		WithDialFunc(dial),
+		WithCommandTimeout(time.Millisecond*50),
+		WithPassword("a"),
+	)
+	require.Nil(t, err)
+	defer conn.Close()
+	assert.Equal(t, `auth "a"`, <-commands)
+
+	buffer := &bytes.Buffer{}
+	assert.Equal(t, context.DeadlineExceeded, errors.Cause(conn.Exec("slow", buffer)))
+	assert.Equal(t, "slow", <-commands)
+
+	assert.Nil(t, conn.Exec("a", buffer))
+	assert.Equal(t, "OK", buffer.String())
+	assert.Equal(t, 2, dials)
+	assert.Equal(t, `auth "a"`, <-commands)
+	assert.Equal(t, "a", <-commands)
+}
+
+func TestDialContext_hostname(t *testing.T) {
+	listener := serveFake(t, func(command string) string {
+		return command + "\n> "
+	})
+	defer listener.Close()
+
+	addr := fmt.Sprintf("localhost:%d", listener.Addr().(*net.TCPAddr).Port)
+	api, err := DialContext(context.Background(), addr, WithDialer(&net.Dialer{Timeout: time.Second}))
+	require.Nil(t, err)
+	defer api.Close()
+
+	result, err := api.Help()
+	assert.Nil(t, err)
+	assert.Equal(t, "help", result)
+}
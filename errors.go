@@ -0,0 +1,85 @@
+package fahapi
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// Errors that can be checked with errors.Is(). Error responses from the client are returned as
+// *CommandError, which matches one of these if the message is recognized.
+var (
+	ErrUnknownCommand = errors.New("unknown command")
+	ErrInvalidSlot    = errors.New("invalid slot")
+	ErrAuthRequired   = errors.New("authentication required")
+	// ErrDisconnected matches *DisconnectError.
+	ErrDisconnected = errors.New("disconnected")
+)
+
+// CommandError is an error response to a command, which is either an "ERROR: " line or a PyON
+// message named "error".
+type CommandError struct {
+	Command string
+	Message string // Error message without "ERROR: "
+	Raw     string // Whole response
+	kind    error
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Command, e.Message)
+}
+
+// Unwrap returns ErrUnknownCommand, ErrInvalidSlot, ErrAuthRequired or nil depending on the
+// message.
+func (e *CommandError) Unwrap() error {
+	return e.kind
+}
+
+// checkResponse returns a *CommandError if response is an error response to command.
+func checkResponse(command string, response []byte) error {
+	var message string
+	if bytes.HasPrefix(response, []byte("PyON")) {
+		name, body, _, err := splitPyONMessage(response)
+		if err != nil || name != "error" {
+			return nil
+		}
+
+		if message, err = ParsePyONString(bytes.TrimSpace(body)); err != nil {
+			message = string(bytes.TrimSpace(body))
+		}
+	} else {
+		found := false
+		for _, line := range strings.Split(string(response), "\n") {
+			if strings.HasPrefix(line, "ERROR:") {
+				message = strings.TrimSpace(strings.TrimPrefix(line, "ERROR:"))
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil
+		}
+	}
+
+	return &CommandError{
+		Command: command,
+		Message: message,
+		Raw:     string(response),
+		kind:    classifyError(message),
+	}
+}
+
+func classifyError(message string) error {
+	message = strings.ToLower(message)
+	switch {
+	case strings.HasPrefix(message, "unknown command"):
+		return ErrUnknownCommand
+	case strings.Contains(message, "invalid slot"):
+		return ErrInvalidSlot
+	case strings.Contains(message, "authentication required"):
+		return ErrAuthRequired
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package fahapi
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCheckResponse(t *testing.T) {
+	tests := []struct {
+		response string
+		message  string // Empty if there is no error
+		kind     error
+	}{
+		{"", "", nil},
+		{"PyON 1 num-slots\n1\n---", "", nil},
+		{"a\nERROR in the middle", "", nil},
+		{"ERROR: unknown command or variable 'a'", "unknown command or variable 'a'", ErrUnknownCommand},
+		{"\nERROR: invalid slot 5", "invalid slot 5", ErrInvalidSlot},
+		{"ERROR: Authentication required", "Authentication required", ErrAuthRequired},
+		{"ERROR: invalid password", "invalid password", nil},
+		{"PyON 1 error\n\"Invalid slot ID\"\n---", "Invalid slot ID", ErrInvalidSlot},
+	}
+
+	for i, test := range tests {
+		err := checkResponse("command", []byte(test.response))
+		if test.message == "" {
+			assert.Nil(t, err, i)
+			continue
+		}
+
+		var commandErr *CommandError
+		if assert.True(t, errors.As(err, &commandErr), i) {
+			assert.Equal(t, "command", commandErr.Command, i)
+			assert.Equal(t, test.message, commandErr.Message, i)
+			assert.Equal(t, test.response, commandErr.Raw, i)
+			assert.Equal(t, "command: "+test.message, err.Error(), i)
+		}
+
+		if test.kind != nil {
+			assert.True(t, errors.Is(err, test.kind), i)
+		} else {
+			assert.Nil(t, errors.Unwrap(err), i)
+		}
+	}
+}
+
+func TestDisconnectError_Is(t *testing.T) {
+	err := errors.WithStack(&DisconnectError{Err: errors.New("a")})
+	assert.True(t, errors.Is(err, ErrDisconnected))
+	assert.False(t, errors.Is(err, ErrInvalidSlot))
+}
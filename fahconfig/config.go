@@ -0,0 +1,171 @@
+// Package fahconfig reads and writes FAHClient config.xml files as fahapi.Config, so that a file
+// can be validated, compared with a running client with API.ImportConfig(config, true), or applied
+// to it.
+package fahconfig
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/MakotoE/go-fahapi"
+	"github.com/pkg/errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// element is an option or a slot in config.xml.
+type element struct {
+	XMLName  xml.Name
+	Value    *string   `xml:"v,attr"`
+	ID       string    `xml:"id,attr"`
+	Type     string    `xml:"type,attr"`
+	Elements []element `xml:",any"`
+}
+
+// Read reads a config.xml file. Options are stored as strings. Slots with type "SMP", which is
+// what older clients call CPU slots, have type fahapi.SlotTypeCPU.
+func Read(r io.Reader) (*fahapi.Config, error) {
+	root := &element{}
+	if err := xml.NewDecoder(r).Decode(root); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if root.XMLName.Local != "config" {
+		return nil, errors.Errorf("root element is %s instead of config", root.XMLName.Local)
+	}
+
+	result := &fahapi.Config{Options: fahapi.OptionChanges{}, Slots: []fahapi.SlotConfig{}}
+	for _, e := range root.Elements {
+		if e.XMLName.Local != "slot" {
+			result.Options[e.XMLName.Local] = e.value()
+			continue
+		}
+
+		slot, err := readSlot(e)
+		if err != nil {
+			return nil, err
+		}
+		result.Slots = append(result.Slots, slot)
+	}
+	return result, nil
+}
+
+func (e *element) value() string {
+	if e.Value == nil {
+		return ""
+	}
+	return *e.Value
+}
+
+func readSlot(e element) (fahapi.SlotConfig, error) {
+	id, err := strconv.Atoi(e.ID)
+	if err != nil {
+		return fahapi.SlotConfig{}, errors.Errorf("invalid slot id: %q", e.ID)
+	}
+
+	result := fahapi.SlotConfig{ID: id, Options: fahapi.SlotChanges{}}
+	switch strings.ToUpper(e.Type) {
+	case "CPU", "SMP":
+		result.Type = fahapi.SlotTypeCPU
+	case "GPU":
+		result.Type = fahapi.SlotTypeGPU
+	default:
+		return fahapi.SlotConfig{}, errors.Errorf("invalid type for slot %d: %q", id, e.Type)
+	}
+
+	for _, option := range e.Elements {
+		result.Options[option.XMLName.Local] = option.value()
+	}
+	return result, nil
+}
+
+// Write writes config as a config.xml file. Options are sorted by name, and options with nil
+// values are left out so that they have their default values.
+func Write(w io.Writer, config *fahapi.Config) error {
+	writer := bufio.NewWriter(w)
+	writer.WriteString("<config>\n")
+	writeOptions(writer, "  ", config.Options)
+
+	for _, slot := range config.Slots {
+		slotType := strings.ToUpper(string(slot.Type))
+		if len(slot.Options) == 0 {
+			fmt.Fprintf(writer, "  <slot id='%d' type='%s'/>\n", slot.ID, escape(slotType))
+			continue
+		}
+
+		fmt.Fprintf(writer, "  <slot id='%d' type='%s'>\n", slot.ID, escape(slotType))
+		writeOptions(writer, "    ", fahapi.OptionChanges(slot.Options))
+		writer.WriteString("  </slot>\n")
+	}
+
+	writer.WriteString("</config>\n")
+	return errors.WithStack(writer.Flush())
+}
+
+func writeOptions(w io.Writer, indent string, options fahapi.OptionChanges) {
+	names := make([]string, 0, len(options))
+	for name, value := range options {
+		if value != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := fmt.Sprintf("%v", options[name])
+		fmt.Fprintf(w, "%s<%s v='%s'/>\n", indent, name, escape(value))
+	}
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	// strings.Builder does not return errors
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// Validate returns an error if config has an unknown option, an invalid value, or duplicate slot
+// IDs.
+func Validate(config *fahapi.Config) error {
+	if err := config.Options.Validate(); err != nil {
+		return err
+	}
+
+	ids := map[int]struct{}{}
+	for _, slot := range config.Slots {
+		if _, ok := ids[slot.ID]; ok {
+			return errors.Errorf("duplicate slot id: %d", slot.ID)
+		}
+		ids[slot.ID] = struct{}{}
+
+		if slot.Type != fahapi.SlotTypeCPU && slot.Type != fahapi.SlotTypeGPU {
+			return errors.Errorf("invalid type for slot %d: %q", slot.ID, slot.Type)
+		}
+
+		if err := slot.Options.Validate(); err != nil {
+			return errors.Wrapf(err, "slot %d", slot.ID)
+		}
+	}
+	return nil
+}
+
+// Options stores the options of config in dst, which is like the result of API.OptionsGet() for a
+// client with the config. Fields of dst for options that are not in config are not modified, so
+// dst should contain the default options.
+func Options(config *fahapi.Config, dst *fahapi.Options) error {
+	options := make(map[string]string, len(config.Options))
+	for name, value := range config.Options {
+		if value != nil {
+			options[name] = fmt.Sprintf("%v", value)
+		}
+	}
+
+	b, err := json.Marshal(options)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(json.Unmarshal(b, dst))
+}
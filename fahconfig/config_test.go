@@ -0,0 +1,179 @@
+package fahconfig
+
+import (
+	"bytes"
+	"context"
+	"github.com/MakotoE/checkerror"
+	"github.com/MakotoE/go-fahapi"
+	"github.com/MakotoE/go-fahapi/fahtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const configXML = `<config>
+  <!-- Folding Slot Configuration -->
+  <power v='full'/>
+  <user v='a &amp; b'/>
+  <team v='1'/>
+  <gpu v='false'/>
+
+  <slot id='0' type='SMP'>
+    <cpus v='4'/>
+    <paused v='true'/>
+  </slot>
+  <slot id='2' type='GPU'/>
+</config>
+`
+
+func TestRead(t *testing.T) {
+	config, err := Read(strings.NewReader(configXML))
+	require.Nil(t, err)
+	assert.Equal(
+		t,
+		&fahapi.Config{
+			Options: fahapi.OptionChanges{
+				"power": "full",
+				"user":  "a & b",
+				"team":  "1",
+				"gpu":   "false",
+			},
+			Slots: []fahapi.SlotConfig{
+				{
+					ID:      0,
+					Type:    fahapi.SlotTypeCPU,
+					Options: fahapi.SlotChanges{"cpus": "4", "paused": "true"},
+				},
+				{ID: 2, Type: fahapi.SlotTypeGPU, Options: fahapi.SlotChanges{}},
+			},
+		},
+		config,
+	)
+	assert.Nil(t, Validate(config))
+
+	tests := []string{
+		"",
+		"<options/>",
+		"<config><slot id='a' type='CPU'/></config>",
+		"<config><slot id='0' type='x'/></config>",
+		"<config>",
+	}
+
+	for i, test := range tests {
+		_, err := Read(strings.NewReader(test))
+		assert.NotNil(t, err, i)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	config, err := Read(strings.NewReader(configXML))
+	require.Nil(t, err)
+	config.Options["passkey"] = nil
+
+	buffer := &bytes.Buffer{}
+	require.Nil(t, Write(buffer, config))
+	assert.Equal(
+		t,
+		`<config>
+  <gpu v='false'/>
+  <power v='full'/>
+  <team v='1'/>
+  <user v='a &amp; b'/>
+  <slot id='0' type='CPU'>
+    <cpus v='4'/>
+    <paused v='true'/>
+  </slot>
+  <slot id='2' type='GPU'/>
+</config>
+`,
+		buffer.String(),
+	)
+
+	delete(config.Options, "passkey")
+	result, err := Read(buffer)
+	require.Nil(t, err)
+	assert.Equal(t, config, result)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		config      *fahapi.Config
+		expectError bool
+	}{
+		{&fahapi.Config{}, false},
+		{&fahapi.Config{Options: fahapi.OptionChanges{"cpu-usage": "101"}}, true},
+		{&fahapi.Config{Options: fahapi.OptionChanges{"a": "b"}}, true},
+		{
+			&fahapi.Config{Slots: []fahapi.SlotConfig{
+				{ID: 0, Type: fahapi.SlotTypeCPU},
+				{ID: 0, Type: fahapi.SlotTypeGPU},
+			}},
+			true,
+		},
+		{&fahapi.Config{Slots: []fahapi.SlotConfig{{ID: 0, Type: "a"}}}, true},
+		{
+			&fahapi.Config{Slots: []fahapi.SlotConfig{
+				{ID: 0, Type: fahapi.SlotTypeCPU, Options: fahapi.SlotChanges{"paused": "a"}},
+			}},
+			true,
+		},
+		{
+			&fahapi.Config{Slots: []fahapi.SlotConfig{
+				{ID: 0, Type: fahapi.SlotTypeCPU, Options: fahapi.SlotChanges{"user": "a"}},
+			}},
+			true,
+		},
+	}
+
+	for i, test := range tests {
+		checkerror.Check(t, test.expectError, Validate(test.config), i)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	config, err := Read(strings.NewReader(configXML))
+	require.Nil(t, err)
+
+	options := &fahapi.Options{Log: "log.txt"}
+	require.Nil(t, Options(config, options))
+	assert.Equal(t, fahapi.PowerFull, options.Power)
+	assert.Equal(t, "a & b", options.User)
+	assert.Equal(t, fahapi.StringInt(1), options.Team)
+	assert.Equal(t, fahapi.StringBool(false), options.Gpu)
+	assert.Equal(t, "log.txt", options.Log)
+
+	config.Options["team"] = "a"
+	assert.NotNil(t, Options(config, options))
+}
+
+func TestRead_importConfig(t *testing.T) {
+	server := fahtest.NewServer()
+	defer server.Close()
+
+	api, err := fahapi.DialContext(context.Background(), server.Addr())
+	require.Nil(t, err)
+	defer api.Close()
+
+	config, err := Read(strings.NewReader(configXML))
+	require.Nil(t, err)
+
+	diff, err := api.ImportConfig(config, true)
+	require.Nil(t, err)
+	assert.Len(t, diff.Options, 4)
+	assert.Equal(t, []fahapi.SlotConfig{config.Slots[1]}, diff.AddSlots)
+	assert.Len(t, diff.ModifySlots, 1)
+
+	_, err = api.ImportConfig(config, false)
+	require.Nil(t, err)
+
+	diff, err = api.ImportConfig(config, true)
+	require.Nil(t, err)
+	assert.Empty(t, diff.Options)
+	assert.Empty(t, diff.ModifySlots)
+
+	exported, err := api.ExportConfig()
+	require.Nil(t, err)
+	assert.Equal(t, "FULL", exported.Options["power"])
+	assert.Equal(t, "a & b", exported.Options["user"])
+}
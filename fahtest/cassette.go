@@ -0,0 +1,294 @@
+package fahtest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/MakotoE/go-fahapi"
+	"github.com/pkg/errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+)
+
+// A cassette is a JSONL file of Interactions, which are written by a Recorder and played back by a
+// Replayer. Each connection starts with an Interaction with an empty command, whose response is
+// the welcome message.
+
+// Interaction is a command and the raw bytes that the client sent before the next command.
+// Messages that the client pushed without a command are included in the response.
+type Interaction struct {
+	Command  string `json:"command"`
+	Response string `json:"response"`
+}
+
+// Recorder is a net.Conn that writes each command sent through it and the bytes that were read in
+// response to a cassette. The password of the auth command is not recorded; it is replaced with
+// "<redacted>", and Replayer matches any auth command to it.
+type Recorder struct {
+	net.Conn
+
+	mutex   sync.Mutex // Guards the fields below; commands and responses are on different goroutines
+	encoder *json.Encoder
+	current Interaction
+	partial []byte // Incomplete command line
+	err     error  // First error from writing the cassette
+}
+
+// NewRecorder returns a Recorder that records conn to w. Each connection, including
+// reconnections, needs its own Recorder, but they can share w if they are not used concurrently.
+func NewRecorder(conn net.Conn, w io.Writer) *Recorder {
+	return &Recorder{Conn: conn, encoder: json.NewEncoder(w)}
+}
+
+// RecordDialFunc returns a fahapi.DialFunc for fahapi.WithDialFunc() that records every connection
+// made by dial to w. If dial is nil, TCP is used.
+func RecordDialFunc(dial fahapi.DialFunc, w io.Writer) fahapi.DialFunc {
+	if dial == nil {
+		dialer := &net.Dialer{}
+		dial = func(ctx context.Context, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "tcp", addr)
+		}
+	}
+
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, addr)
+		if err != nil {
+			return nil, err
+		}
+		return NewRecorder(conn, w), nil
+	}
+}
+
+func (r *Recorder) Read(b []byte) (int, error) {
+	n, err := r.Conn.Read(b)
+
+	r.mutex.Lock()
+	r.current.Response += string(b[:n])
+	r.mutex.Unlock()
+	return n, err
+}
+
+func (r *Recorder) Write(b []byte) (int, error) {
+	r.mutex.Lock()
+	r.partial = append(r.partial, b...)
+	for {
+		i := bytes.IndexByte(r.partial, '\n')
+		if i == -1 {
+			break
+		}
+
+		r.flush()
+		r.current.Command = redact(string(r.partial[:i]))
+		r.partial = r.partial[i+1:]
+	}
+	r.mutex.Unlock()
+
+	return r.Conn.Write(b)
+}
+
+// redactedAuth is the auth command in cassettes.
+const redactedAuth = `auth "<redacted>"`
+
+// redact hides the password of an auth command.
+func redact(command string) string {
+	if command == "auth" || strings.HasPrefix(command, "auth ") {
+		return redactedAuth
+	}
+	return command
+}
+
+// Close writes the last interaction and closes the connection.
+func (r *Recorder) Close() error {
+	r.mutex.Lock()
+	r.flush()
+	err := r.err
+	r.mutex.Unlock()
+
+	if closeErr := r.Conn.Close(); err == nil {
+		err = closeErr
+	}
+	return errors.WithStack(err)
+}
+
+// flush must be called with r.mutex held.
+func (r *Recorder) flush() {
+	if err := r.encoder.Encode(r.current); err != nil && r.err == nil {
+		r.err = err
+	}
+	r.current = Interaction{}
+}
+
+// ReadCassette reads the interactions in a cassette.
+func ReadCassette(r io.Reader) ([]Interaction, error) {
+	var interactions []Interaction
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<26) // The log can be large
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var interaction Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &interaction); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		interactions = append(interactions, interaction)
+	}
+	return interactions, errors.WithStack(scanner.Err())
+}
+
+// Match is how a Replayer matches commands to recorded interactions.
+type Match int
+
+const (
+	// MatchStrict requires commands to be sent in the recorded order. Connections are closed where
+	// the recorded connections ended.
+	MatchStrict Match = iota
+	// MatchLoose finds the next recorded interaction with the same command, in any order and on
+	// any connection. The last one is repeated once all have been used.
+	MatchLoose
+)
+
+// Replayer plays back a cassette. Use Dial with fahapi.WithDialFunc() to connect to it.
+type Replayer struct {
+	match Match
+
+	mutex        sync.Mutex // Guards the fields below
+	interactions []Interaction
+	next         int            // Index of the next interaction for MatchStrict
+	used         map[string]int // Number of times each command was matched for MatchLoose
+	err          error
+}
+
+// NewReplayer returns a Replayer for the interactions of a cassette.
+func NewReplayer(interactions []Interaction, match Match) *Replayer {
+	return &Replayer{interactions: interactions, match: match, used: map[string]int{}}
+}
+
+// Dial is a fahapi.DialFunc that returns a connection to the replayed client. addr is ignored.
+func (r *Replayer) Dial(ctx context.Context, addr string) (net.Conn, error) {
+	welcome, err := r.welcome()
+	if err != nil {
+		return nil, err
+	}
+
+	client, server := net.Pipe()
+	go r.serve(server, welcome)
+	return client, nil
+}
+
+// Err returns the first command that did not match the cassette.
+func (r *Replayer) Err() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.err
+}
+
+// Remaining returns the number of interactions that were not replayed with MatchStrict.
+func (r *Replayer) Remaining() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return len(r.interactions) - r.next
+}
+
+func (r *Replayer) welcome() (string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.match == MatchLoose {
+		for _, interaction := range r.interactions {
+			if interaction.Command == "" {
+				return interaction.Response, nil
+			}
+		}
+		return "", errors.New("cassette has no connections")
+	}
+
+	if r.next >= len(r.interactions) || r.interactions[r.next].Command != "" {
+		return "", r.mismatch("connection")
+	}
+
+	r.next++
+	return r.interactions[r.next-1].Response, nil
+}
+
+func (r *Replayer) serve(conn net.Conn, welcome string) {
+	defer conn.Close()
+
+	if _, err := io.WriteString(conn, welcome); err != nil {
+		return
+	}
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		response, last := r.respond(scanner.Text())
+		if _, err := io.WriteString(conn, response); err != nil || last {
+			return
+		}
+	}
+}
+
+// respond returns the response to command. last is true if the recorded connection ended after
+// the response.
+func (r *Replayer) respond(command string) (response string, last bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	command = redact(command)
+
+	if r.match == MatchLoose {
+		var found []Interaction
+		for _, interaction := range r.interactions {
+			if interaction.Command == command {
+				found = append(found, interaction)
+			}
+		}
+
+		if len(found) == 0 {
+			return "\nERROR: " + r.mismatch(command).Error() + "\n> ", false
+		}
+
+		i := r.used[command]
+		if i >= len(found) {
+			i = len(found) - 1
+		}
+		r.used[command]++
+		return found[i].Response, false
+	}
+
+	if r.next >= len(r.interactions) || r.interactions[r.next].Command != command {
+		return "\nERROR: " + r.mismatch(command).Error() + "\n> ", false
+	}
+
+	r.next++
+	last = r.next == len(r.interactions) || r.interactions[r.next].Command == ""
+	return r.interactions[r.next-1].Response, last
+}
+
+// mismatch saves and returns an error for an unexpected command. It must be called with r.mutex
+// held.
+func (r *Replayer) mismatch(command string) error {
+	expected := "end of cassette"
+	if r.match == MatchStrict && r.next < len(r.interactions) {
+		expected = fmt.Sprintf("%q", r.interactions[r.next].Command)
+		if r.interactions[r.next].Command == "" {
+			expected = "connection"
+		}
+	}
+
+	err := errors.Errorf("fahtest: unexpected %q; expected %s", command, expected)
+	if r.match == MatchLoose {
+		err = errors.Errorf("fahtest: %q is not in the cassette", command)
+	}
+
+	if r.err == nil {
+		r.err = err
+	}
+	return err
+}
@@ -0,0 +1,157 @@
+package fahtest
+
+import (
+	"bytes"
+	"context"
+	"github.com/MakotoE/go-fahapi"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// record runs f with an API that records to a cassette and returns the cassette.
+func record(t *testing.T, server *Server, f func(api *fahapi.API)) []Interaction {
+	buffer := &bytes.Buffer{}
+	api, err := fahapi.DialContext(
+		context.Background(),
+		server.Addr(),
+		fahapi.WithDialFunc(RecordDialFunc(nil, buffer)),
+	)
+	require.Nil(t, err)
+	f(api)
+	require.Nil(t, api.Close())
+
+	interactions, err := ReadCassette(buffer)
+	require.Nil(t, err)
+	return interactions
+}
+
+func TestRecorder(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	interactions := record(t, server, func(api *fahapi.API) {
+		_, err := api.NumSlots()
+		require.Nil(t, err)
+		require.Nil(t, api.PauseAll())
+	})
+
+	assert.Equal(
+		t,
+		[]Interaction{
+			{"", Welcome + "\n> "},
+			{"num-slots", "\nPyON 1 num-slots\n1\n---\n> "},
+			{"pause", "\n> "},
+		},
+		interactions,
+	)
+}
+
+func TestRecorder_auth(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.SetPassword("secret")
+
+	interactions := record(t, server, func(api *fahapi.API) {
+		require.Nil(t, api.Auth("secret"))
+	})
+
+	require.Len(t, interactions, 2)
+	assert.Equal(t, `auth "<redacted>"`, interactions[1].Command)
+
+	buffer := &bytes.Buffer{}
+	for _, interaction := range interactions {
+		buffer.WriteString(interaction.Command + interaction.Response)
+	}
+	assert.NotContains(t, buffer.String(), "secret")
+
+	// Any password matches
+	for _, match := range []Match{MatchStrict, MatchLoose} {
+		replayer := NewReplayer(interactions, match)
+		api, err := fahapi.DialContext(
+			context.Background(),
+			"",
+			fahapi.WithDialFunc(replayer.Dial),
+		)
+		require.Nil(t, err, match)
+
+		assert.Nil(t, api.Auth("other"), match)
+		assert.Nil(t, replayer.Err(), match)
+		assert.Nil(t, api.Close(), match)
+	}
+}
+
+func TestReplayer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	interactions := record(t, server, func(api *fahapi.API) {
+		_, err := api.NumSlots()
+		require.Nil(t, err)
+		_, err = api.SlotInfo()
+		require.Nil(t, err)
+	})
+
+	{
+		replayer := NewReplayer(interactions, MatchStrict)
+		api, err := fahapi.DialContext(
+			context.Background(),
+			"",
+			fahapi.WithDialFunc(replayer.Dial),
+		)
+		require.Nil(t, err)
+
+		n, err := api.NumSlots()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+
+		slots, err := api.SlotInfo()
+		assert.Nil(t, err)
+		assert.Len(t, slots, 1)
+
+		assert.Nil(t, api.Close())
+		assert.Nil(t, replayer.Err())
+		assert.Equal(t, 0, replayer.Remaining())
+	}
+	{
+		replayer := NewReplayer(interactions, MatchStrict)
+		api, err := fahapi.DialContext(
+			context.Background(),
+			"",
+			fahapi.WithDialFunc(replayer.Dial),
+		)
+		require.Nil(t, err)
+		defer api.Close()
+
+		// Out of order
+		_, err = api.SlotInfo()
+		assert.NotNil(t, err)
+		assert.NotNil(t, replayer.Err())
+	}
+	{
+		replayer := NewReplayer(interactions, MatchLoose)
+		api, err := fahapi.DialContext(
+			context.Background(),
+			"",
+			fahapi.WithDialFunc(replayer.Dial),
+		)
+		require.Nil(t, err)
+		defer api.Close()
+
+		slots, err := api.SlotInfo()
+		assert.Nil(t, err)
+		assert.Len(t, slots, 1)
+
+		for i := 0; i < 2; i++ {
+			n, err := api.NumSlots()
+			assert.Nil(t, err, i)
+			assert.Equal(t, 1, n, i)
+		}
+		assert.Nil(t, replayer.Err())
+
+		var commandErr *fahapi.CommandError
+		assert.True(t, errors.As(api.Exec("uptime", &bytes.Buffer{}), &commandErr))
+		assert.NotNil(t, replayer.Err())
+	}
+}
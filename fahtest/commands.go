@@ -0,0 +1,540 @@
+package fahtest
+
+import (
+	"fmt"
+	"github.com/MakotoE/go-fahapi"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// builtin returns the simulated command named name, or nil.
+func (s *Server) builtin(name string) HandlerFunc {
+	switch name {
+	case "help":
+		return s.help
+	case "screensaver", "do-cycle", "download-core", "request-id", "request-ws", "save",
+		"shutdown", "wait-for-units":
+		return func([]string) (string, error) {
+			return "", nil
+		}
+	case "always_on":
+		return s.forSlots(func(slot *Slot) {
+			slot.Options["idle"] = "false"
+			slot.Idle = false
+		})
+	case "on_idle":
+		return s.forSlots(func(slot *Slot) {
+			slot.Options["idle"] = "true"
+			slot.Idle = true
+		})
+	case "pause":
+		return s.forSlots(func(slot *Slot) {
+			slot.setPaused(true)
+		})
+	case "unpause":
+		return s.forSlots(func(slot *Slot) {
+			slot.setPaused(false)
+		})
+	case "finish":
+		return s.forSlots(func(slot *Slot) {
+			slot.Options["finish"] = "true"
+			slot.Status = "FINISHING"
+		})
+	case "configured":
+		return s.configured
+	case "eval":
+		return s.eval
+	case "info":
+		return s.info
+	case "num-slots":
+		return s.numSlots
+	case "options":
+		return s.optionsCommand
+	case "ppd":
+		return s.ppd
+	case "queue-info":
+		return s.queueInfo
+	case "simulation-info":
+		return s.simulationInfo
+	case "slot-add":
+		return s.slotAdd
+	case "slot-delete":
+		return s.slotDelete
+	case "slot-info":
+		return s.slotInfo
+	case "slot-modify":
+		return s.slotModify
+	case "slot-options":
+		return s.slotOptions
+	case "uptime":
+		return s.uptime
+	}
+	return nil
+}
+
+func pyon(name string, v interface{}) (string, error) {
+	b, err := fahapi.MarshalPyON(name, v)
+	return string(b), err
+}
+
+func (s *Server) help([]string) (string, error) {
+	return "fahtest simulates these commands:\n" +
+		"  always_on auth configured do-cycle download-core eval exit finish help info\n" +
+		"  log-updates num-slots on_idle options pause ppd queue-info quit request-id\n" +
+		"  request-ws save screensaver shutdown simulation-info slot-add slot-delete\n" +
+		"  slot-info slot-modify slot-options unpause updates uptime wait-for-units", nil
+}
+
+// findSlot must be called with s.mutex held.
+func (s *Server) findSlot(arg string) (*Slot, error) {
+	id, err := strconv.Atoi(arg)
+	if err == nil {
+		for _, slot := range s.slots {
+			if slot.ID == id {
+				return slot, nil
+			}
+		}
+	}
+
+	return nil, errors.Errorf("invalid slot %s", arg)
+}
+
+// forSlots returns a command that calls f for the slot given as the argument, or all slots.
+func (s *Server) forSlots(f func(slot *Slot)) HandlerFunc {
+	return func(args []string) (string, error) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		if len(args) == 0 {
+			for _, slot := range s.slots {
+				f(slot)
+			}
+			return "", nil
+		}
+
+		slot, err := s.findSlot(args[0])
+		if err != nil {
+			return "", err
+		}
+
+		f(slot)
+		return "", nil
+	}
+}
+
+func (s *Server) configured([]string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	configured := s.options["user"] != "Anonymous" ||
+		s.options["team"] != "0" ||
+		s.options["passkey"] != ""
+	return pyon("configured", configured)
+}
+
+// eval expands commands in its argument. Like FAHClient, an escaped newline at the end leaves a
+// trailing backslash.
+func (s *Server) eval(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("eval requires one argument")
+	}
+
+	text := args[0]
+	suffix := ""
+	if strings.HasSuffix(text, `\n`) {
+		text = strings.TrimSuffix(text, `\n`)
+		suffix = `\`
+	}
+
+	result, err := s.expand(text)
+	return result + suffix, err
+}
+
+func (s *Server) info([]string) (string, error) {
+	s.mutex.Lock()
+	cpus := 0
+	for _, slot := range s.slots {
+		if strings.HasPrefix(slot.Description, "cpu:") {
+			n, _ := strconv.Atoi(strings.TrimPrefix(slot.Description, "cpu:"))
+			cpus += n
+		}
+	}
+	s.mutex.Unlock()
+
+	build := func(section string) []interface{} {
+		return []interface{}{
+			section,
+			[]interface{}{"Date", "Jul 1 2020"},
+			[]interface{}{"Time", "12:00:00"},
+			[]interface{}{"Revision", "0000000000000000000000000000000000000000"},
+			[]interface{}{"Branch", "master"},
+			[]interface{}{"Compiler", "GNU 8.3.0"},
+			[]interface{}{"Options", "-std=c++11 -O3"},
+			[]interface{}{"Platform", "linux2 4.19.0-5-amd64"},
+			[]interface{}{"Bits", "64"},
+			[]interface{}{"Mode", "Release"},
+		}
+	}
+
+	client := append(build("FAHClient"),
+		[]interface{}{"Version", "7.6.21"},
+		[]interface{}{"Author", "Joseph Coffland <joseph@cauldrondevelopment.com>"},
+		[]interface{}{"Copyright", "2020 foldingathome.org"},
+		[]interface{}{"Homepage", "https://foldingathome.org/"},
+		[]interface{}{"Args", "--config /etc/fahclient/config.xml"},
+		[]interface{}{"Config", "/etc/fahclient/config.xml"},
+	)
+
+	system := []interface{}{
+		"System",
+		[]interface{}{"CPU", "fahtest CPU"},
+		[]interface{}{"CPU ID", "fahtest"},
+		[]interface{}{"CPUs", strconv.Itoa(cpus)},
+		[]interface{}{"Memory", "16.00GiB"},
+		[]interface{}{"Free Memory", "8.00GiB"},
+		[]interface{}{"Threads", "POSIX_THREADS"},
+		[]interface{}{"OS Version", "4.19"},
+		[]interface{}{"Has Battery", "false"},
+		[]interface{}{"On Battery", "false"},
+		[]interface{}{"UTC Offset", "0"},
+		[]interface{}{"PID", "1"},
+		[]interface{}{"CWD", "/var/lib/fahclient"},
+		[]interface{}{"OS", "Linux 4.19.0-5-amd64 x86_64"},
+		[]interface{}{"OS Arch", "AMD64"},
+		[]interface{}{"GPUs", "0"},
+	}
+
+	return pyon("info", []interface{}{client, build("CBang"), system, build("libFAH")})
+}
+
+func (s *Server) numSlots([]string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return pyon("num-slots", len(s.slots))
+}
+
+// optionsCommand gets options with "key" arguments, sets them with "key=value" arguments and resets
+// them with "key!" arguments. Without keys, the response contains options with non-default values,
+// and also options with default values for "-d", and also unset options for "-a".
+func (s *Server) optionsCommand(args []string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	defaults := defaultOptions()
+	result := map[string]string{}
+	listDefaults := false
+	listUnset := false
+	named := false
+	for _, arg := range args {
+		if arg == "-d" {
+			listDefaults = true
+			continue
+		} else if arg == "-a" {
+			listDefaults = true
+			listUnset = true
+			continue
+		}
+
+		named = true
+		if i := strings.IndexByte(arg, '='); i > 0 {
+			s.options[arg[:i]] = arg[i+1:]
+			result[arg[:i]] = arg[i+1:]
+		} else if value, ok := defaults[strings.TrimSuffix(arg, "!")]; ok && arg[len(arg)-1] == '!' {
+			s.options[arg[:len(arg)-1]] = value
+			result[arg[:len(arg)-1]] = value
+		} else if value, ok := s.options[arg]; ok {
+			result[arg] = value
+		} else {
+			return "", errors.Errorf("unknown option %s", arg)
+		}
+	}
+
+	if !named {
+		for key, value := range s.options {
+			if value != defaults[key] || (listDefaults && value != "") || listUnset {
+				result[key] = value
+			}
+		}
+	}
+	return pyon("options", result)
+}
+
+func (s *Server) ppd([]string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ppd := 0.0
+	for _, unit := range s.units {
+		ppd += float64(unit.PPD)
+	}
+	return pyon("ppd", ppd)
+}
+
+func (s *Server) queueInfo([]string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return pyon("units", append([]fahapi.SlotQueueInfo{}, s.units...))
+}
+
+func (s *Server) simulationInfo(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("simulation-info requires a slot")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	slot, err := s.findSlot(args[0])
+	if err != nil {
+		return "", err
+	}
+
+	info := fahapi.SimulationInfo{
+		User: s.options["user"],
+		Team: s.options["team"],
+		Slot: slot.ID,
+	}
+
+	for _, unit := range s.units {
+		if unit.Slot == fmt.Sprintf("%02d", slot.ID) {
+			info.Project = unit.Project
+			info.Run = unit.Run
+			info.Clone = unit.Clone
+			info.Gen = unit.Gen
+			info.Core = unit.Core
+			info.TotalIterations = unit.TotalFrames
+			info.IterationsDone = unit.FramesDone
+			info.StartTime = unit.Assigned
+			info.ETA = int(time.Duration(unit.ETA) / time.Second)
+			if unit.TotalFrames > 0 {
+				info.Progress = float64(unit.FramesDone) / float64(unit.TotalFrames)
+			}
+			break
+		}
+	}
+
+	return pyon("simulation-info", info)
+}
+
+// slotDescription returns the description of a slot with the given type and options, like
+// "cpu:4" or "gpu:0:fahtest GPU".
+func slotDescription(slotType string, options map[string]string) (string, error) {
+	switch slotType {
+	case "cpu":
+		cpus := 4
+		if n, err := strconv.Atoi(options["cpus"]); err == nil && n > 0 {
+			cpus = n
+		}
+		return fmt.Sprintf("cpu:%d", cpus), nil
+	case "gpu":
+		index := "0"
+		if options["gpu-index"] != "" {
+			index = options["gpu-index"]
+		}
+		return fmt.Sprintf("gpu:%s:fahtest GPU", index), nil
+	}
+	return "", errors.Errorf("invalid slot type %s", slotType)
+}
+
+// resetSlotOption sets an option to its default, or deletes it if it has no default.
+func resetSlotOption(options map[string]string, name string) {
+	if value, ok := slotDefaults[name]; ok {
+		options[name] = value
+	} else {
+		delete(options, name)
+	}
+}
+
+// applySlotChanges sets options given as "name=value" and resets options given as "name!".
+func applySlotChanges(options map[string]string, args []string) error {
+	for _, arg := range args {
+		if i := strings.IndexByte(arg, '='); i > 0 {
+			options[arg[:i]] = arg[i+1:]
+		} else if strings.HasSuffix(arg, "!") && len(arg) > 1 {
+			resetSlotOption(options, strings.TrimSuffix(arg, "!"))
+		} else {
+			return errors.Errorf("invalid slot option %s", arg)
+		}
+	}
+	return nil
+}
+
+func (s *Server) slotAdd(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", errors.New("slot-add requires a slot type")
+	}
+
+	options := map[string]string{}
+	for _, arg := range args[1:] {
+		if strings.HasSuffix(arg, "!") {
+			return "", errors.Errorf("invalid slot option %s", arg)
+		}
+	}
+	if err := applySlotChanges(options, args[1:]); err != nil {
+		return "", err
+	}
+
+	description, err := slotDescription(args[0], options)
+	if err != nil {
+		return "", err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.addSlot(description, options)
+	return "", nil
+}
+
+func (s *Server) slotDelete(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("slot-delete requires a slot")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	slot, err := s.findSlot(args[0])
+	if err != nil {
+		return "", err
+	}
+
+	for i := range s.slots {
+		if s.slots[i] == slot {
+			s.slots = append(s.slots[:i], s.slots[i+1:]...)
+			break
+		}
+	}
+
+	units := s.units[:0]
+	for _, unit := range s.units {
+		if unit.Slot != fmt.Sprintf("%02d", slot.ID) {
+			units = append(units, unit)
+		}
+	}
+	s.units = units
+	return "", nil
+}
+
+func (s *Server) slotModify(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", errors.New("slot-modify requires a slot and a slot type")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	slot, err := s.findSlot(args[0])
+	if err != nil {
+		return "", err
+	}
+
+	options := slot.copy().Options
+	if err := applySlotChanges(options, args[2:]); err != nil {
+		return "", err
+	}
+
+	description, err := slotDescription(args[1], options)
+	if err != nil {
+		return "", err
+	}
+
+	slot.Options = options
+	slot.Description = description
+	return "", nil
+}
+
+func (s *Server) slotInfo([]string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	result := make([]fahapi.SlotInfo, 0, len(s.slots))
+	for _, slot := range s.slots {
+		options := map[string]interface{}{}
+		for key, value := range slot.Options {
+			options[key] = value
+		}
+
+		result = append(result, fahapi.SlotInfo{
+			ID:          fmt.Sprintf("%02d", slot.ID),
+			Status:      slot.Status,
+			Description: slot.Description,
+			Options:     options,
+			Reason:      slot.Reason,
+			Idle:        slot.Idle,
+		})
+	}
+	return pyon("slots", result)
+}
+
+// slotOptions returns options of a slot with non-default values, or all options with "-a" or
+// "-d". It sets options given as key value pairs and resets options given as "key!".
+func (s *Server) slotOptions(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", errors.New("slot-options requires a slot")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	slot, err := s.findSlot(args[0])
+	if err != nil {
+		return "", err
+	}
+
+	args = args[1:]
+	if len(args) == 1 && (args[0] == "-a" || args[0] == "-d") {
+		return pyon("slot-options", slot.Options)
+	} else if len(args) == 0 {
+		changed := map[string]string{}
+		for key, value := range slot.Options {
+			if value != slotDefaults[key] {
+				changed[key] = value
+			}
+		}
+		return pyon("slot-options", changed)
+	}
+
+	options := slot.copy().Options
+	for len(args) > 0 {
+		if strings.HasSuffix(args[0], "!") {
+			resetSlotOption(options, strings.TrimSuffix(args[0], "!"))
+			args = args[1:]
+			continue
+		}
+
+		if len(args) == 1 {
+			return "", errors.New("slot-options requires key value pairs")
+		}
+		options[args[0]] = args[1]
+		args = args[2:]
+	}
+
+	paused, err := strconv.ParseBool(options["paused"])
+	if err != nil {
+		return "", errors.Errorf("invalid paused value: %s", options["paused"])
+	}
+
+	if options["cpus"] != slot.Options["cpus"] || options["gpu-index"] != slot.Options["gpu-index"] {
+		description, err := slotDescription(strings.SplitN(slot.Description, ":", 2)[0], options)
+		if err != nil {
+			return "", err
+		}
+		slot.Description = description
+	}
+
+	changed := options["paused"] != slot.Options["paused"]
+	slot.Options = options
+	if changed {
+		slot.setPaused(paused)
+	}
+	return "", nil
+}
+
+func (s *Server) uptime([]string) (string, error) {
+	return fahapi.FormatFAHDuration(fahapi.FAHDuration(time.Since(s.started))), nil
+}
@@ -0,0 +1,30 @@
+package fahtest
+
+import (
+	"github.com/MakotoE/go-fahapi"
+	"github.com/pkg/errors"
+)
+
+// ErrNotMocked is matched by the errors of MockClient methods whose function is not set.
+var ErrNotMocked = errors.New("not mocked")
+
+var _ fahapi.Client = (*MockClient)(nil)
+
+// Calls returns the names of the methods that were called, in order.
+func (m *MockClient) Calls() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return append([]string(nil), m.calls...)
+}
+
+func (m *MockClient) record(method string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.calls = append(m.calls, method)
+}
+
+func notMocked(method string) error {
+	return errors.WithMessage(ErrNotMocked, "fahtest: "+method)
+}
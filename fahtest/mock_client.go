@@ -0,0 +1,1397 @@
+// Code generated by genclient from the methods of API; DO NOT EDIT.
+
+package fahtest
+
+import (
+	"bytes"
+	"context"
+	"github.com/MakotoE/go-fahapi"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// MockClient is a fahapi.Client for tests. Each method calls the field with the name of the method
+// and a Func suffix, or returns an error that matches ErrNotMocked if the field is nil. The names
+// of the called methods are recorded.
+type MockClient struct {
+	AlwaysOnFunc                   func(slot int) error
+	AlwaysOnContextFunc            func(ctx context.Context, slot int) error
+	AlwaysOnVerifiedFunc           func(slot int, timeout time.Duration) error
+	AlwaysOnVerifiedContextFunc    func(ctx context.Context, slot int, timeout time.Duration) error
+	AuthFunc                       func(password string) error
+	AuthContextFunc                func(ctx context.Context, password string) error
+	ConfiguredFunc                 func() (bool, error)
+	ConfiguredContextFunc          func(ctx context.Context) (bool, error)
+	DiffFunc                       func(config *fahapi.Config) (*fahapi.Diff, error)
+	DiffContextFunc                func(ctx context.Context, config *fahapi.Config) (*fahapi.Diff, error)
+	DoCycleFunc                    func() error
+	DoCycleContextFunc             func(ctx context.Context) error
+	DownloadCoreFunc               func(coreType string, url *url.URL) error
+	DownloadCoreContextFunc        func(ctx context.Context, coreType string, url *url.URL) error
+	ExecFunc                       func(command string, buffer *bytes.Buffer) error
+	ExecContextFunc                func(ctx context.Context, command string, buffer *bytes.Buffer) error
+	ExecEvalFunc                   func(command string, buffer *bytes.Buffer) error
+	ExecEvalContextFunc            func(ctx context.Context, command string, buffer *bytes.Buffer) error
+	ExportConfigFunc               func() (*fahapi.Config, error)
+	ExportConfigContextFunc        func(ctx context.Context) (*fahapi.Config, error)
+	FinishFunc                     func(slot int) error
+	FinishAllFunc                  func() error
+	FinishAllContextFunc           func(ctx context.Context) error
+	FinishSlotFunc                 func(slot int) error
+	FinishSlotContextFunc          func(ctx context.Context, slot int) error
+	FinishSlotVerifiedFunc         func(slot int, timeout time.Duration) error
+	FinishSlotVerifiedContextFunc  func(ctx context.Context, slot int, timeout time.Duration) error
+	HelpFunc                       func() (string, error)
+	HelpContextFunc                func(ctx context.Context) (string, error)
+	ImportConfigFunc               func(config *fahapi.Config, dryRun bool) (*fahapi.Diff, error)
+	ImportConfigContextFunc        func(ctx context.Context, config *fahapi.Config, dryRun bool) (*fahapi.Diff, error)
+	InfoFunc                       func() ([][]interface{}, error)
+	InfoContextFunc                func(ctx context.Context) ([][]interface{}, error)
+	InfoStructFunc                 func(dst *fahapi.Info) error
+	InfoStructContextFunc          func(ctx context.Context, dst *fahapi.Info) error
+	LogUpdatesFunc                 func(arg fahapi.LogUpdatesArg) (string, error)
+	LogUpdatesContextFunc          func(ctx context.Context, arg fahapi.LogUpdatesArg) (string, error)
+	NumSlotsFunc                   func() (int, error)
+	NumSlotsContextFunc            func(ctx context.Context) (int, error)
+	OnIdleFunc                     func(slot int) error
+	OnIdleAllFunc                  func() error
+	OnIdleAllContextFunc           func(ctx context.Context) error
+	OnIdleContextFunc              func(ctx context.Context, slot int) error
+	OnIdleVerifiedFunc             func(slot int, timeout time.Duration) error
+	OnIdleVerifiedContextFunc      func(ctx context.Context, slot int, timeout time.Duration) error
+	OptionsApplyFunc               func(patch *fahapi.OptionsPatch, dst *fahapi.Options) error
+	OptionsApplyContextFunc        func(ctx context.Context, patch *fahapi.OptionsPatch, dst *fahapi.Options) error
+	OptionsChangedFunc             func() (map[string]string, error)
+	OptionsChangedContextFunc      func(ctx context.Context) (map[string]string, error)
+	OptionsGetFunc                 func(dst *fahapi.Options) error
+	OptionsGetContextFunc          func(ctx context.Context, dst *fahapi.Options) error
+	OptionsResetFunc               func(dst *fahapi.Options, keys ...string) error
+	OptionsResetContextFunc        func(ctx context.Context, dst *fahapi.Options, keys ...string) error
+	OptionsSetFunc                 func(key string, value interface{}) error
+	OptionsSetContextFunc          func(ctx context.Context, key string, value interface{}) error
+	OptionsUpdateFunc              func(changes fahapi.OptionChanges, dst *fahapi.Options) error
+	OptionsUpdateContextFunc       func(ctx context.Context, changes fahapi.OptionChanges, dst *fahapi.Options) error
+	OptionsWithDefaultsFunc        func() (map[string]string, error)
+	OptionsWithDefaultsContextFunc func(ctx context.Context) (map[string]string, error)
+	PPDFunc                        func() (float64, error)
+	PPDContextFunc                 func(ctx context.Context) (float64, error)
+	PauseAllFunc                   func() error
+	PauseAllContextFunc            func(ctx context.Context) error
+	PauseSlotFunc                  func(slot int) error
+	PauseSlotContextFunc           func(ctx context.Context, slot int) error
+	PauseSlotVerifiedFunc          func(slot int, timeout time.Duration) error
+	PauseSlotVerifiedContextFunc   func(ctx context.Context, slot int, timeout time.Duration) error
+	QueueInfoFunc                  func() ([]fahapi.SlotQueueInfo, error)
+	QueueInfoContextFunc           func(ctx context.Context) ([]fahapi.SlotQueueInfo, error)
+	ReconcileFunc                  func(config *fahapi.Config, dryRun bool) (*fahapi.Diff, error)
+	ReconcileContextFunc           func(ctx context.Context, config *fahapi.Config, dryRun bool) (*fahapi.Diff, error)
+	RequestIDFunc                  func() error
+	RequestIDContextFunc           func(ctx context.Context) error
+	RequestWSFunc                  func() error
+	RequestWSContextFunc           func(ctx context.Context) error
+	SaveFunc                       func(path string) error
+	SaveContextFunc                func(ctx context.Context, path string) error
+	ScreensaverFunc                func() error
+	ScreensaverContextFunc         func(ctx context.Context) error
+	ShutdownFunc                   func() error
+	ShutdownContextFunc            func(ctx context.Context) error
+	SimulationInfoFunc             func(slot int, dst *fahapi.SimulationInfo) error
+	SimulationInfoContextFunc      func(ctx context.Context, slot int, dst *fahapi.SimulationInfo) error
+	SlotAddFunc                    func(slotType fahapi.SlotType, changes fahapi.SlotChanges) (int, error)
+	SlotAddContextFunc             func(ctx context.Context, slotType fahapi.SlotType, changes fahapi.SlotChanges) (int, error)
+	SlotDeleteFunc                 func(slot int) error
+	SlotDeleteContextFunc          func(ctx context.Context, slot int) error
+	SlotDeleteVerifiedFunc         func(slot int, timeout time.Duration) error
+	SlotDeleteVerifiedContextFunc  func(ctx context.Context, slot int, timeout time.Duration) error
+	SlotInfoFunc                   func() ([]fahapi.SlotInfo, error)
+	SlotInfoContextFunc            func(ctx context.Context) ([]fahapi.SlotInfo, error)
+	SlotModifyFunc                 func(slot int, slotType fahapi.SlotType, changes fahapi.SlotChanges) error
+	SlotModifyContextFunc          func(ctx context.Context, slot int, slotType fahapi.SlotType, changes fahapi.SlotChanges) error
+	SlotOptionsGetFunc             func(slot int, dst *fahapi.SlotOptions) error
+	SlotOptionsGetContextFunc      func(ctx context.Context, slot int, dst *fahapi.SlotOptions) error
+	SlotOptionsResetFunc           func(slot int, keys ...string) error
+	SlotOptionsResetContextFunc    func(ctx context.Context, slot int, keys ...string) error
+	SlotOptionsSetFunc             func(slot int, key string, value interface{}) error
+	SlotOptionsSetContextFunc      func(ctx context.Context, slot int, key string, value interface{}) error
+	SlotOptionsUpdateFunc          func(slot int, changes fahapi.SlotChanges) error
+	SlotOptionsUpdateContextFunc   func(ctx context.Context, slot int, changes fahapi.SlotChanges) error
+	SubscribeLogFunc               func(ctx context.Context) (<-chan fahapi.LogLine, error)
+	UnpauseAllFunc                 func() error
+	UnpauseAllContextFunc          func(ctx context.Context) error
+	UnpauseSlotFunc                func(slot int) error
+	UnpauseSlotContextFunc         func(ctx context.Context, slot int) error
+	UnpauseSlotVerifiedFunc        func(slot int, timeout time.Duration) error
+	UnpauseSlotVerifiedContextFunc func(ctx context.Context, slot int, timeout time.Duration) error
+	UpdatesAddFunc                 func(id int, rate time.Duration, expression string) error
+	UpdatesAddContextFunc          func(ctx context.Context, id int, rate time.Duration, expression string) error
+	UpdatesClearFunc               func() error
+	UpdatesClearContextFunc        func(ctx context.Context) error
+	UpdatesDelFunc                 func(id int) error
+	UpdatesDelContextFunc          func(ctx context.Context, id int) error
+	UpdatesListFunc                func() (string, error)
+	UpdatesListContextFunc         func(ctx context.Context) (string, error)
+	UpdatesResetFunc               func() error
+	UpdatesResetContextFunc        func(ctx context.Context) error
+	UptimeFunc                     func() (fahapi.FAHDuration, error)
+	UptimeContextFunc              func(ctx context.Context) (fahapi.FAHDuration, error)
+	WaitForUnitsFunc               func() error
+	WaitForUnitsContextFunc        func(ctx context.Context) error
+	WatchEventsFunc                func(ctx context.Context, rate time.Duration) (<-chan fahapi.WatchEvent, error)
+	WatchOptionsFunc               func(ctx context.Context, rate time.Duration) (<-chan fahapi.Options, error)
+	WatchQueueInfoFunc             func(ctx context.Context, rate time.Duration) (<-chan []fahapi.SlotQueueInfo, error)
+	WatchSlotInfoFunc              func(ctx context.Context, rate time.Duration) (<-chan []fahapi.SlotInfo, error)
+
+	mutex sync.Mutex
+	calls []string
+}
+
+// AlwaysOn calls AlwaysOnFunc.
+func (m *MockClient) AlwaysOn(slot int) error {
+	m.record("AlwaysOn")
+	if m.AlwaysOnFunc == nil {
+		return notMocked("AlwaysOn")
+	}
+	return m.AlwaysOnFunc(slot)
+}
+
+// AlwaysOnContext calls AlwaysOnContextFunc.
+func (m *MockClient) AlwaysOnContext(ctx context.Context, slot int) error {
+	m.record("AlwaysOnContext")
+	if m.AlwaysOnContextFunc == nil {
+		return notMocked("AlwaysOnContext")
+	}
+	return m.AlwaysOnContextFunc(ctx, slot)
+}
+
+// AlwaysOnVerified calls AlwaysOnVerifiedFunc.
+func (m *MockClient) AlwaysOnVerified(slot int, timeout time.Duration) error {
+	m.record("AlwaysOnVerified")
+	if m.AlwaysOnVerifiedFunc == nil {
+		return notMocked("AlwaysOnVerified")
+	}
+	return m.AlwaysOnVerifiedFunc(slot, timeout)
+}
+
+// AlwaysOnVerifiedContext calls AlwaysOnVerifiedContextFunc.
+func (m *MockClient) AlwaysOnVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	m.record("AlwaysOnVerifiedContext")
+	if m.AlwaysOnVerifiedContextFunc == nil {
+		return notMocked("AlwaysOnVerifiedContext")
+	}
+	return m.AlwaysOnVerifiedContextFunc(ctx, slot, timeout)
+}
+
+// Auth calls AuthFunc.
+func (m *MockClient) Auth(password string) error {
+	m.record("Auth")
+	if m.AuthFunc == nil {
+		return notMocked("Auth")
+	}
+	return m.AuthFunc(password)
+}
+
+// AuthContext calls AuthContextFunc.
+func (m *MockClient) AuthContext(ctx context.Context, password string) error {
+	m.record("AuthContext")
+	if m.AuthContextFunc == nil {
+		return notMocked("AuthContext")
+	}
+	return m.AuthContextFunc(ctx, password)
+}
+
+// Configured calls ConfiguredFunc.
+func (m *MockClient) Configured() (result bool, err error) {
+	m.record("Configured")
+	if m.ConfiguredFunc == nil {
+		return result, notMocked("Configured")
+	}
+	return m.ConfiguredFunc()
+}
+
+// ConfiguredContext calls ConfiguredContextFunc.
+func (m *MockClient) ConfiguredContext(ctx context.Context) (result bool, err error) {
+	m.record("ConfiguredContext")
+	if m.ConfiguredContextFunc == nil {
+		return result, notMocked("ConfiguredContext")
+	}
+	return m.ConfiguredContextFunc(ctx)
+}
+
+// Diff calls DiffFunc.
+func (m *MockClient) Diff(config *fahapi.Config) (result *fahapi.Diff, err error) {
+	m.record("Diff")
+	if m.DiffFunc == nil {
+		return result, notMocked("Diff")
+	}
+	return m.DiffFunc(config)
+}
+
+// DiffContext calls DiffContextFunc.
+func (m *MockClient) DiffContext(
+	ctx context.Context,
+	config *fahapi.Config,
+) (result *fahapi.Diff, err error) {
+	m.record("DiffContext")
+	if m.DiffContextFunc == nil {
+		return result, notMocked("DiffContext")
+	}
+	return m.DiffContextFunc(ctx, config)
+}
+
+// DoCycle calls DoCycleFunc.
+func (m *MockClient) DoCycle() error {
+	m.record("DoCycle")
+	if m.DoCycleFunc == nil {
+		return notMocked("DoCycle")
+	}
+	return m.DoCycleFunc()
+}
+
+// DoCycleContext calls DoCycleContextFunc.
+func (m *MockClient) DoCycleContext(ctx context.Context) error {
+	m.record("DoCycleContext")
+	if m.DoCycleContextFunc == nil {
+		return notMocked("DoCycleContext")
+	}
+	return m.DoCycleContextFunc(ctx)
+}
+
+// DownloadCore calls DownloadCoreFunc.
+func (m *MockClient) DownloadCore(coreType string, url *url.URL) error {
+	m.record("DownloadCore")
+	if m.DownloadCoreFunc == nil {
+		return notMocked("DownloadCore")
+	}
+	return m.DownloadCoreFunc(coreType, url)
+}
+
+// DownloadCoreContext calls DownloadCoreContextFunc.
+func (m *MockClient) DownloadCoreContext(ctx context.Context, coreType string, url *url.URL) error {
+	m.record("DownloadCoreContext")
+	if m.DownloadCoreContextFunc == nil {
+		return notMocked("DownloadCoreContext")
+	}
+	return m.DownloadCoreContextFunc(ctx, coreType, url)
+}
+
+// Exec calls ExecFunc.
+func (m *MockClient) Exec(command string, buffer *bytes.Buffer) error {
+	m.record("Exec")
+	if m.ExecFunc == nil {
+		return notMocked("Exec")
+	}
+	return m.ExecFunc(command, buffer)
+}
+
+// ExecContext calls ExecContextFunc.
+func (m *MockClient) ExecContext(ctx context.Context, command string, buffer *bytes.Buffer) error {
+	m.record("ExecContext")
+	if m.ExecContextFunc == nil {
+		return notMocked("ExecContext")
+	}
+	return m.ExecContextFunc(ctx, command, buffer)
+}
+
+// ExecEval calls ExecEvalFunc.
+func (m *MockClient) ExecEval(command string, buffer *bytes.Buffer) error {
+	m.record("ExecEval")
+	if m.ExecEvalFunc == nil {
+		return notMocked("ExecEval")
+	}
+	return m.ExecEvalFunc(command, buffer)
+}
+
+// ExecEvalContext calls ExecEvalContextFunc.
+func (m *MockClient) ExecEvalContext(
+	ctx context.Context,
+	command string,
+	buffer *bytes.Buffer,
+) error {
+	m.record("ExecEvalContext")
+	if m.ExecEvalContextFunc == nil {
+		return notMocked("ExecEvalContext")
+	}
+	return m.ExecEvalContextFunc(ctx, command, buffer)
+}
+
+// ExportConfig calls ExportConfigFunc.
+func (m *MockClient) ExportConfig() (result *fahapi.Config, err error) {
+	m.record("ExportConfig")
+	if m.ExportConfigFunc == nil {
+		return result, notMocked("ExportConfig")
+	}
+	return m.ExportConfigFunc()
+}
+
+// ExportConfigContext calls ExportConfigContextFunc.
+func (m *MockClient) ExportConfigContext(ctx context.Context) (result *fahapi.Config, err error) {
+	m.record("ExportConfigContext")
+	if m.ExportConfigContextFunc == nil {
+		return result, notMocked("ExportConfigContext")
+	}
+	return m.ExportConfigContextFunc(ctx)
+}
+
+// Finish calls FinishFunc.
+func (m *MockClient) Finish(slot int) error {
+	m.record("Finish")
+	if m.FinishFunc == nil {
+		return notMocked("Finish")
+	}
+	return m.FinishFunc(slot)
+}
+
+// FinishAll calls FinishAllFunc.
+func (m *MockClient) FinishAll() error {
+	m.record("FinishAll")
+	if m.FinishAllFunc == nil {
+		return notMocked("FinishAll")
+	}
+	return m.FinishAllFunc()
+}
+
+// FinishAllContext calls FinishAllContextFunc.
+func (m *MockClient) FinishAllContext(ctx context.Context) error {
+	m.record("FinishAllContext")
+	if m.FinishAllContextFunc == nil {
+		return notMocked("FinishAllContext")
+	}
+	return m.FinishAllContextFunc(ctx)
+}
+
+// FinishSlot calls FinishSlotFunc.
+func (m *MockClient) FinishSlot(slot int) error {
+	m.record("FinishSlot")
+	if m.FinishSlotFunc == nil {
+		return notMocked("FinishSlot")
+	}
+	return m.FinishSlotFunc(slot)
+}
+
+// FinishSlotContext calls FinishSlotContextFunc.
+func (m *MockClient) FinishSlotContext(ctx context.Context, slot int) error {
+	m.record("FinishSlotContext")
+	if m.FinishSlotContextFunc == nil {
+		return notMocked("FinishSlotContext")
+	}
+	return m.FinishSlotContextFunc(ctx, slot)
+}
+
+// FinishSlotVerified calls FinishSlotVerifiedFunc.
+func (m *MockClient) FinishSlotVerified(slot int, timeout time.Duration) error {
+	m.record("FinishSlotVerified")
+	if m.FinishSlotVerifiedFunc == nil {
+		return notMocked("FinishSlotVerified")
+	}
+	return m.FinishSlotVerifiedFunc(slot, timeout)
+}
+
+// FinishSlotVerifiedContext calls FinishSlotVerifiedContextFunc.
+func (m *MockClient) FinishSlotVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	m.record("FinishSlotVerifiedContext")
+	if m.FinishSlotVerifiedContextFunc == nil {
+		return notMocked("FinishSlotVerifiedContext")
+	}
+	return m.FinishSlotVerifiedContextFunc(ctx, slot, timeout)
+}
+
+// Help calls HelpFunc.
+func (m *MockClient) Help() (result string, err error) {
+	m.record("Help")
+	if m.HelpFunc == nil {
+		return result, notMocked("Help")
+	}
+	return m.HelpFunc()
+}
+
+// HelpContext calls HelpContextFunc.
+func (m *MockClient) HelpContext(ctx context.Context) (result string, err error) {
+	m.record("HelpContext")
+	if m.HelpContextFunc == nil {
+		return result, notMocked("HelpContext")
+	}
+	return m.HelpContextFunc(ctx)
+}
+
+// ImportConfig calls ImportConfigFunc.
+func (m *MockClient) ImportConfig(
+	config *fahapi.Config,
+	dryRun bool,
+) (result *fahapi.Diff, err error) {
+	m.record("ImportConfig")
+	if m.ImportConfigFunc == nil {
+		return result, notMocked("ImportConfig")
+	}
+	return m.ImportConfigFunc(config, dryRun)
+}
+
+// ImportConfigContext calls ImportConfigContextFunc.
+func (m *MockClient) ImportConfigContext(
+	ctx context.Context,
+	config *fahapi.Config,
+	dryRun bool,
+) (result *fahapi.Diff, err error) {
+	m.record("ImportConfigContext")
+	if m.ImportConfigContextFunc == nil {
+		return result, notMocked("ImportConfigContext")
+	}
+	return m.ImportConfigContextFunc(ctx, config, dryRun)
+}
+
+// Info calls InfoFunc.
+func (m *MockClient) Info() (result [][]interface{}, err error) {
+	m.record("Info")
+	if m.InfoFunc == nil {
+		return result, notMocked("Info")
+	}
+	return m.InfoFunc()
+}
+
+// InfoContext calls InfoContextFunc.
+func (m *MockClient) InfoContext(ctx context.Context) (result [][]interface{}, err error) {
+	m.record("InfoContext")
+	if m.InfoContextFunc == nil {
+		return result, notMocked("InfoContext")
+	}
+	return m.InfoContextFunc(ctx)
+}
+
+// InfoStruct calls InfoStructFunc.
+func (m *MockClient) InfoStruct(dst *fahapi.Info) error {
+	m.record("InfoStruct")
+	if m.InfoStructFunc == nil {
+		return notMocked("InfoStruct")
+	}
+	return m.InfoStructFunc(dst)
+}
+
+// InfoStructContext calls InfoStructContextFunc.
+func (m *MockClient) InfoStructContext(ctx context.Context, dst *fahapi.Info) error {
+	m.record("InfoStructContext")
+	if m.InfoStructContextFunc == nil {
+		return notMocked("InfoStructContext")
+	}
+	return m.InfoStructContextFunc(ctx, dst)
+}
+
+// LogUpdates calls LogUpdatesFunc.
+func (m *MockClient) LogUpdates(arg fahapi.LogUpdatesArg) (result string, err error) {
+	m.record("LogUpdates")
+	if m.LogUpdatesFunc == nil {
+		return result, notMocked("LogUpdates")
+	}
+	return m.LogUpdatesFunc(arg)
+}
+
+// LogUpdatesContext calls LogUpdatesContextFunc.
+func (m *MockClient) LogUpdatesContext(
+	ctx context.Context,
+	arg fahapi.LogUpdatesArg,
+) (result string, err error) {
+	m.record("LogUpdatesContext")
+	if m.LogUpdatesContextFunc == nil {
+		return result, notMocked("LogUpdatesContext")
+	}
+	return m.LogUpdatesContextFunc(ctx, arg)
+}
+
+// NumSlots calls NumSlotsFunc.
+func (m *MockClient) NumSlots() (result int, err error) {
+	m.record("NumSlots")
+	if m.NumSlotsFunc == nil {
+		return result, notMocked("NumSlots")
+	}
+	return m.NumSlotsFunc()
+}
+
+// NumSlotsContext calls NumSlotsContextFunc.
+func (m *MockClient) NumSlotsContext(ctx context.Context) (result int, err error) {
+	m.record("NumSlotsContext")
+	if m.NumSlotsContextFunc == nil {
+		return result, notMocked("NumSlotsContext")
+	}
+	return m.NumSlotsContextFunc(ctx)
+}
+
+// OnIdle calls OnIdleFunc.
+func (m *MockClient) OnIdle(slot int) error {
+	m.record("OnIdle")
+	if m.OnIdleFunc == nil {
+		return notMocked("OnIdle")
+	}
+	return m.OnIdleFunc(slot)
+}
+
+// OnIdleAll calls OnIdleAllFunc.
+func (m *MockClient) OnIdleAll() error {
+	m.record("OnIdleAll")
+	if m.OnIdleAllFunc == nil {
+		return notMocked("OnIdleAll")
+	}
+	return m.OnIdleAllFunc()
+}
+
+// OnIdleAllContext calls OnIdleAllContextFunc.
+func (m *MockClient) OnIdleAllContext(ctx context.Context) error {
+	m.record("OnIdleAllContext")
+	if m.OnIdleAllContextFunc == nil {
+		return notMocked("OnIdleAllContext")
+	}
+	return m.OnIdleAllContextFunc(ctx)
+}
+
+// OnIdleContext calls OnIdleContextFunc.
+func (m *MockClient) OnIdleContext(ctx context.Context, slot int) error {
+	m.record("OnIdleContext")
+	if m.OnIdleContextFunc == nil {
+		return notMocked("OnIdleContext")
+	}
+	return m.OnIdleContextFunc(ctx, slot)
+}
+
+// OnIdleVerified calls OnIdleVerifiedFunc.
+func (m *MockClient) OnIdleVerified(slot int, timeout time.Duration) error {
+	m.record("OnIdleVerified")
+	if m.OnIdleVerifiedFunc == nil {
+		return notMocked("OnIdleVerified")
+	}
+	return m.OnIdleVerifiedFunc(slot, timeout)
+}
+
+// OnIdleVerifiedContext calls OnIdleVerifiedContextFunc.
+func (m *MockClient) OnIdleVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	m.record("OnIdleVerifiedContext")
+	if m.OnIdleVerifiedContextFunc == nil {
+		return notMocked("OnIdleVerifiedContext")
+	}
+	return m.OnIdleVerifiedContextFunc(ctx, slot, timeout)
+}
+
+// OptionsApply calls OptionsApplyFunc.
+func (m *MockClient) OptionsApply(patch *fahapi.OptionsPatch, dst *fahapi.Options) error {
+	m.record("OptionsApply")
+	if m.OptionsApplyFunc == nil {
+		return notMocked("OptionsApply")
+	}
+	return m.OptionsApplyFunc(patch, dst)
+}
+
+// OptionsApplyContext calls OptionsApplyContextFunc.
+func (m *MockClient) OptionsApplyContext(
+	ctx context.Context,
+	patch *fahapi.OptionsPatch,
+	dst *fahapi.Options,
+) error {
+	m.record("OptionsApplyContext")
+	if m.OptionsApplyContextFunc == nil {
+		return notMocked("OptionsApplyContext")
+	}
+	return m.OptionsApplyContextFunc(ctx, patch, dst)
+}
+
+// OptionsChanged calls OptionsChangedFunc.
+func (m *MockClient) OptionsChanged() (result map[string]string, err error) {
+	m.record("OptionsChanged")
+	if m.OptionsChangedFunc == nil {
+		return result, notMocked("OptionsChanged")
+	}
+	return m.OptionsChangedFunc()
+}
+
+// OptionsChangedContext calls OptionsChangedContextFunc.
+func (m *MockClient) OptionsChangedContext(
+	ctx context.Context,
+) (result map[string]string, err error) {
+	m.record("OptionsChangedContext")
+	if m.OptionsChangedContextFunc == nil {
+		return result, notMocked("OptionsChangedContext")
+	}
+	return m.OptionsChangedContextFunc(ctx)
+}
+
+// OptionsGet calls OptionsGetFunc.
+func (m *MockClient) OptionsGet(dst *fahapi.Options) error {
+	m.record("OptionsGet")
+	if m.OptionsGetFunc == nil {
+		return notMocked("OptionsGet")
+	}
+	return m.OptionsGetFunc(dst)
+}
+
+// OptionsGetContext calls OptionsGetContextFunc.
+func (m *MockClient) OptionsGetContext(ctx context.Context, dst *fahapi.Options) error {
+	m.record("OptionsGetContext")
+	if m.OptionsGetContextFunc == nil {
+		return notMocked("OptionsGetContext")
+	}
+	return m.OptionsGetContextFunc(ctx, dst)
+}
+
+// OptionsReset calls OptionsResetFunc.
+func (m *MockClient) OptionsReset(dst *fahapi.Options, keys ...string) error {
+	m.record("OptionsReset")
+	if m.OptionsResetFunc == nil {
+		return notMocked("OptionsReset")
+	}
+	return m.OptionsResetFunc(dst, keys...)
+}
+
+// OptionsResetContext calls OptionsResetContextFunc.
+func (m *MockClient) OptionsResetContext(
+	ctx context.Context,
+	dst *fahapi.Options,
+	keys ...string,
+) error {
+	m.record("OptionsResetContext")
+	if m.OptionsResetContextFunc == nil {
+		return notMocked("OptionsResetContext")
+	}
+	return m.OptionsResetContextFunc(ctx, dst, keys...)
+}
+
+// OptionsSet calls OptionsSetFunc.
+func (m *MockClient) OptionsSet(key string, value interface{}) error {
+	m.record("OptionsSet")
+	if m.OptionsSetFunc == nil {
+		return notMocked("OptionsSet")
+	}
+	return m.OptionsSetFunc(key, value)
+}
+
+// OptionsSetContext calls OptionsSetContextFunc.
+func (m *MockClient) OptionsSetContext(ctx context.Context, key string, value interface{}) error {
+	m.record("OptionsSetContext")
+	if m.OptionsSetContextFunc == nil {
+		return notMocked("OptionsSetContext")
+	}
+	return m.OptionsSetContextFunc(ctx, key, value)
+}
+
+// OptionsUpdate calls OptionsUpdateFunc.
+func (m *MockClient) OptionsUpdate(changes fahapi.OptionChanges, dst *fahapi.Options) error {
+	m.record("OptionsUpdate")
+	if m.OptionsUpdateFunc == nil {
+		return notMocked("OptionsUpdate")
+	}
+	return m.OptionsUpdateFunc(changes, dst)
+}
+
+// OptionsUpdateContext calls OptionsUpdateContextFunc.
+func (m *MockClient) OptionsUpdateContext(
+	ctx context.Context,
+	changes fahapi.OptionChanges,
+	dst *fahapi.Options,
+) error {
+	m.record("OptionsUpdateContext")
+	if m.OptionsUpdateContextFunc == nil {
+		return notMocked("OptionsUpdateContext")
+	}
+	return m.OptionsUpdateContextFunc(ctx, changes, dst)
+}
+
+// OptionsWithDefaults calls OptionsWithDefaultsFunc.
+func (m *MockClient) OptionsWithDefaults() (result map[string]string, err error) {
+	m.record("OptionsWithDefaults")
+	if m.OptionsWithDefaultsFunc == nil {
+		return result, notMocked("OptionsWithDefaults")
+	}
+	return m.OptionsWithDefaultsFunc()
+}
+
+// OptionsWithDefaultsContext calls OptionsWithDefaultsContextFunc.
+func (m *MockClient) OptionsWithDefaultsContext(
+	ctx context.Context,
+) (result map[string]string, err error) {
+	m.record("OptionsWithDefaultsContext")
+	if m.OptionsWithDefaultsContextFunc == nil {
+		return result, notMocked("OptionsWithDefaultsContext")
+	}
+	return m.OptionsWithDefaultsContextFunc(ctx)
+}
+
+// PPD calls PPDFunc.
+func (m *MockClient) PPD() (result float64, err error) {
+	m.record("PPD")
+	if m.PPDFunc == nil {
+		return result, notMocked("PPD")
+	}
+	return m.PPDFunc()
+}
+
+// PPDContext calls PPDContextFunc.
+func (m *MockClient) PPDContext(ctx context.Context) (result float64, err error) {
+	m.record("PPDContext")
+	if m.PPDContextFunc == nil {
+		return result, notMocked("PPDContext")
+	}
+	return m.PPDContextFunc(ctx)
+}
+
+// PauseAll calls PauseAllFunc.
+func (m *MockClient) PauseAll() error {
+	m.record("PauseAll")
+	if m.PauseAllFunc == nil {
+		return notMocked("PauseAll")
+	}
+	return m.PauseAllFunc()
+}
+
+// PauseAllContext calls PauseAllContextFunc.
+func (m *MockClient) PauseAllContext(ctx context.Context) error {
+	m.record("PauseAllContext")
+	if m.PauseAllContextFunc == nil {
+		return notMocked("PauseAllContext")
+	}
+	return m.PauseAllContextFunc(ctx)
+}
+
+// PauseSlot calls PauseSlotFunc.
+func (m *MockClient) PauseSlot(slot int) error {
+	m.record("PauseSlot")
+	if m.PauseSlotFunc == nil {
+		return notMocked("PauseSlot")
+	}
+	return m.PauseSlotFunc(slot)
+}
+
+// PauseSlotContext calls PauseSlotContextFunc.
+func (m *MockClient) PauseSlotContext(ctx context.Context, slot int) error {
+	m.record("PauseSlotContext")
+	if m.PauseSlotContextFunc == nil {
+		return notMocked("PauseSlotContext")
+	}
+	return m.PauseSlotContextFunc(ctx, slot)
+}
+
+// PauseSlotVerified calls PauseSlotVerifiedFunc.
+func (m *MockClient) PauseSlotVerified(slot int, timeout time.Duration) error {
+	m.record("PauseSlotVerified")
+	if m.PauseSlotVerifiedFunc == nil {
+		return notMocked("PauseSlotVerified")
+	}
+	return m.PauseSlotVerifiedFunc(slot, timeout)
+}
+
+// PauseSlotVerifiedContext calls PauseSlotVerifiedContextFunc.
+func (m *MockClient) PauseSlotVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	m.record("PauseSlotVerifiedContext")
+	if m.PauseSlotVerifiedContextFunc == nil {
+		return notMocked("PauseSlotVerifiedContext")
+	}
+	return m.PauseSlotVerifiedContextFunc(ctx, slot, timeout)
+}
+
+// QueueInfo calls QueueInfoFunc.
+func (m *MockClient) QueueInfo() (result []fahapi.SlotQueueInfo, err error) {
+	m.record("QueueInfo")
+	if m.QueueInfoFunc == nil {
+		return result, notMocked("QueueInfo")
+	}
+	return m.QueueInfoFunc()
+}
+
+// QueueInfoContext calls QueueInfoContextFunc.
+func (m *MockClient) QueueInfoContext(
+	ctx context.Context,
+) (result []fahapi.SlotQueueInfo, err error) {
+	m.record("QueueInfoContext")
+	if m.QueueInfoContextFunc == nil {
+		return result, notMocked("QueueInfoContext")
+	}
+	return m.QueueInfoContextFunc(ctx)
+}
+
+// Reconcile calls ReconcileFunc.
+func (m *MockClient) Reconcile(
+	config *fahapi.Config,
+	dryRun bool,
+) (result *fahapi.Diff, err error) {
+	m.record("Reconcile")
+	if m.ReconcileFunc == nil {
+		return result, notMocked("Reconcile")
+	}
+	return m.ReconcileFunc(config, dryRun)
+}
+
+// ReconcileContext calls ReconcileContextFunc.
+func (m *MockClient) ReconcileContext(
+	ctx context.Context,
+	config *fahapi.Config,
+	dryRun bool,
+) (result *fahapi.Diff, err error) {
+	m.record("ReconcileContext")
+	if m.ReconcileContextFunc == nil {
+		return result, notMocked("ReconcileContext")
+	}
+	return m.ReconcileContextFunc(ctx, config, dryRun)
+}
+
+// RequestID calls RequestIDFunc.
+func (m *MockClient) RequestID() error {
+	m.record("RequestID")
+	if m.RequestIDFunc == nil {
+		return notMocked("RequestID")
+	}
+	return m.RequestIDFunc()
+}
+
+// RequestIDContext calls RequestIDContextFunc.
+func (m *MockClient) RequestIDContext(ctx context.Context) error {
+	m.record("RequestIDContext")
+	if m.RequestIDContextFunc == nil {
+		return notMocked("RequestIDContext")
+	}
+	return m.RequestIDContextFunc(ctx)
+}
+
+// RequestWS calls RequestWSFunc.
+func (m *MockClient) RequestWS() error {
+	m.record("RequestWS")
+	if m.RequestWSFunc == nil {
+		return notMocked("RequestWS")
+	}
+	return m.RequestWSFunc()
+}
+
+// RequestWSContext calls RequestWSContextFunc.
+func (m *MockClient) RequestWSContext(ctx context.Context) error {
+	m.record("RequestWSContext")
+	if m.RequestWSContextFunc == nil {
+		return notMocked("RequestWSContext")
+	}
+	return m.RequestWSContextFunc(ctx)
+}
+
+// Save calls SaveFunc.
+func (m *MockClient) Save(path string) error {
+	m.record("Save")
+	if m.SaveFunc == nil {
+		return notMocked("Save")
+	}
+	return m.SaveFunc(path)
+}
+
+// SaveContext calls SaveContextFunc.
+func (m *MockClient) SaveContext(ctx context.Context, path string) error {
+	m.record("SaveContext")
+	if m.SaveContextFunc == nil {
+		return notMocked("SaveContext")
+	}
+	return m.SaveContextFunc(ctx, path)
+}
+
+// Screensaver calls ScreensaverFunc.
+func (m *MockClient) Screensaver() error {
+	m.record("Screensaver")
+	if m.ScreensaverFunc == nil {
+		return notMocked("Screensaver")
+	}
+	return m.ScreensaverFunc()
+}
+
+// ScreensaverContext calls ScreensaverContextFunc.
+func (m *MockClient) ScreensaverContext(ctx context.Context) error {
+	m.record("ScreensaverContext")
+	if m.ScreensaverContextFunc == nil {
+		return notMocked("ScreensaverContext")
+	}
+	return m.ScreensaverContextFunc(ctx)
+}
+
+// Shutdown calls ShutdownFunc.
+func (m *MockClient) Shutdown() error {
+	m.record("Shutdown")
+	if m.ShutdownFunc == nil {
+		return notMocked("Shutdown")
+	}
+	return m.ShutdownFunc()
+}
+
+// ShutdownContext calls ShutdownContextFunc.
+func (m *MockClient) ShutdownContext(ctx context.Context) error {
+	m.record("ShutdownContext")
+	if m.ShutdownContextFunc == nil {
+		return notMocked("ShutdownContext")
+	}
+	return m.ShutdownContextFunc(ctx)
+}
+
+// SimulationInfo calls SimulationInfoFunc.
+func (m *MockClient) SimulationInfo(slot int, dst *fahapi.SimulationInfo) error {
+	m.record("SimulationInfo")
+	if m.SimulationInfoFunc == nil {
+		return notMocked("SimulationInfo")
+	}
+	return m.SimulationInfoFunc(slot, dst)
+}
+
+// SimulationInfoContext calls SimulationInfoContextFunc.
+func (m *MockClient) SimulationInfoContext(
+	ctx context.Context,
+	slot int,
+	dst *fahapi.SimulationInfo,
+) error {
+	m.record("SimulationInfoContext")
+	if m.SimulationInfoContextFunc == nil {
+		return notMocked("SimulationInfoContext")
+	}
+	return m.SimulationInfoContextFunc(ctx, slot, dst)
+}
+
+// SlotAdd calls SlotAddFunc.
+func (m *MockClient) SlotAdd(
+	slotType fahapi.SlotType,
+	changes fahapi.SlotChanges,
+) (result int, err error) {
+	m.record("SlotAdd")
+	if m.SlotAddFunc == nil {
+		return result, notMocked("SlotAdd")
+	}
+	return m.SlotAddFunc(slotType, changes)
+}
+
+// SlotAddContext calls SlotAddContextFunc.
+func (m *MockClient) SlotAddContext(
+	ctx context.Context,
+	slotType fahapi.SlotType,
+	changes fahapi.SlotChanges,
+) (result int, err error) {
+	m.record("SlotAddContext")
+	if m.SlotAddContextFunc == nil {
+		return result, notMocked("SlotAddContext")
+	}
+	return m.SlotAddContextFunc(ctx, slotType, changes)
+}
+
+// SlotDelete calls SlotDeleteFunc.
+func (m *MockClient) SlotDelete(slot int) error {
+	m.record("SlotDelete")
+	if m.SlotDeleteFunc == nil {
+		return notMocked("SlotDelete")
+	}
+	return m.SlotDeleteFunc(slot)
+}
+
+// SlotDeleteContext calls SlotDeleteContextFunc.
+func (m *MockClient) SlotDeleteContext(ctx context.Context, slot int) error {
+	m.record("SlotDeleteContext")
+	if m.SlotDeleteContextFunc == nil {
+		return notMocked("SlotDeleteContext")
+	}
+	return m.SlotDeleteContextFunc(ctx, slot)
+}
+
+// SlotDeleteVerified calls SlotDeleteVerifiedFunc.
+func (m *MockClient) SlotDeleteVerified(slot int, timeout time.Duration) error {
+	m.record("SlotDeleteVerified")
+	if m.SlotDeleteVerifiedFunc == nil {
+		return notMocked("SlotDeleteVerified")
+	}
+	return m.SlotDeleteVerifiedFunc(slot, timeout)
+}
+
+// SlotDeleteVerifiedContext calls SlotDeleteVerifiedContextFunc.
+func (m *MockClient) SlotDeleteVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	m.record("SlotDeleteVerifiedContext")
+	if m.SlotDeleteVerifiedContextFunc == nil {
+		return notMocked("SlotDeleteVerifiedContext")
+	}
+	return m.SlotDeleteVerifiedContextFunc(ctx, slot, timeout)
+}
+
+// SlotInfo calls SlotInfoFunc.
+func (m *MockClient) SlotInfo() (result []fahapi.SlotInfo, err error) {
+	m.record("SlotInfo")
+	if m.SlotInfoFunc == nil {
+		return result, notMocked("SlotInfo")
+	}
+	return m.SlotInfoFunc()
+}
+
+// SlotInfoContext calls SlotInfoContextFunc.
+func (m *MockClient) SlotInfoContext(ctx context.Context) (result []fahapi.SlotInfo, err error) {
+	m.record("SlotInfoContext")
+	if m.SlotInfoContextFunc == nil {
+		return result, notMocked("SlotInfoContext")
+	}
+	return m.SlotInfoContextFunc(ctx)
+}
+
+// SlotModify calls SlotModifyFunc.
+func (m *MockClient) SlotModify(
+	slot int,
+	slotType fahapi.SlotType,
+	changes fahapi.SlotChanges,
+) error {
+	m.record("SlotModify")
+	if m.SlotModifyFunc == nil {
+		return notMocked("SlotModify")
+	}
+	return m.SlotModifyFunc(slot, slotType, changes)
+}
+
+// SlotModifyContext calls SlotModifyContextFunc.
+func (m *MockClient) SlotModifyContext(
+	ctx context.Context,
+	slot int,
+	slotType fahapi.SlotType,
+	changes fahapi.SlotChanges,
+) error {
+	m.record("SlotModifyContext")
+	if m.SlotModifyContextFunc == nil {
+		return notMocked("SlotModifyContext")
+	}
+	return m.SlotModifyContextFunc(ctx, slot, slotType, changes)
+}
+
+// SlotOptionsGet calls SlotOptionsGetFunc.
+func (m *MockClient) SlotOptionsGet(slot int, dst *fahapi.SlotOptions) error {
+	m.record("SlotOptionsGet")
+	if m.SlotOptionsGetFunc == nil {
+		return notMocked("SlotOptionsGet")
+	}
+	return m.SlotOptionsGetFunc(slot, dst)
+}
+
+// SlotOptionsGetContext calls SlotOptionsGetContextFunc.
+func (m *MockClient) SlotOptionsGetContext(
+	ctx context.Context,
+	slot int,
+	dst *fahapi.SlotOptions,
+) error {
+	m.record("SlotOptionsGetContext")
+	if m.SlotOptionsGetContextFunc == nil {
+		return notMocked("SlotOptionsGetContext")
+	}
+	return m.SlotOptionsGetContextFunc(ctx, slot, dst)
+}
+
+// SlotOptionsReset calls SlotOptionsResetFunc.
+func (m *MockClient) SlotOptionsReset(slot int, keys ...string) error {
+	m.record("SlotOptionsReset")
+	if m.SlotOptionsResetFunc == nil {
+		return notMocked("SlotOptionsReset")
+	}
+	return m.SlotOptionsResetFunc(slot, keys...)
+}
+
+// SlotOptionsResetContext calls SlotOptionsResetContextFunc.
+func (m *MockClient) SlotOptionsResetContext(ctx context.Context, slot int, keys ...string) error {
+	m.record("SlotOptionsResetContext")
+	if m.SlotOptionsResetContextFunc == nil {
+		return notMocked("SlotOptionsResetContext")
+	}
+	return m.SlotOptionsResetContextFunc(ctx, slot, keys...)
+}
+
+// SlotOptionsSet calls SlotOptionsSetFunc.
+func (m *MockClient) SlotOptionsSet(slot int, key string, value interface{}) error {
+	m.record("SlotOptionsSet")
+	if m.SlotOptionsSetFunc == nil {
+		return notMocked("SlotOptionsSet")
+	}
+	return m.SlotOptionsSetFunc(slot, key, value)
+}
+
+// SlotOptionsSetContext calls SlotOptionsSetContextFunc.
+func (m *MockClient) SlotOptionsSetContext(
+	ctx context.Context,
+	slot int,
+	key string,
+	value interface{},
+) error {
+	m.record("SlotOptionsSetContext")
+	if m.SlotOptionsSetContextFunc == nil {
+		return notMocked("SlotOptionsSetContext")
+	}
+	return m.SlotOptionsSetContextFunc(ctx, slot, key, value)
+}
+
+// SlotOptionsUpdate calls SlotOptionsUpdateFunc.
+func (m *MockClient) SlotOptionsUpdate(slot int, changes fahapi.SlotChanges) error {
+	m.record("SlotOptionsUpdate")
+	if m.SlotOptionsUpdateFunc == nil {
+		return notMocked("SlotOptionsUpdate")
+	}
+	return m.SlotOptionsUpdateFunc(slot, changes)
+}
+
+// SlotOptionsUpdateContext calls SlotOptionsUpdateContextFunc.
+func (m *MockClient) SlotOptionsUpdateContext(
+	ctx context.Context,
+	slot int,
+	changes fahapi.SlotChanges,
+) error {
+	m.record("SlotOptionsUpdateContext")
+	if m.SlotOptionsUpdateContextFunc == nil {
+		return notMocked("SlotOptionsUpdateContext")
+	}
+	return m.SlotOptionsUpdateContextFunc(ctx, slot, changes)
+}
+
+// SubscribeLog calls SubscribeLogFunc.
+func (m *MockClient) SubscribeLog(ctx context.Context) (result <-chan fahapi.LogLine, err error) {
+	m.record("SubscribeLog")
+	if m.SubscribeLogFunc == nil {
+		return result, notMocked("SubscribeLog")
+	}
+	return m.SubscribeLogFunc(ctx)
+}
+
+// UnpauseAll calls UnpauseAllFunc.
+func (m *MockClient) UnpauseAll() error {
+	m.record("UnpauseAll")
+	if m.UnpauseAllFunc == nil {
+		return notMocked("UnpauseAll")
+	}
+	return m.UnpauseAllFunc()
+}
+
+// UnpauseAllContext calls UnpauseAllContextFunc.
+func (m *MockClient) UnpauseAllContext(ctx context.Context) error {
+	m.record("UnpauseAllContext")
+	if m.UnpauseAllContextFunc == nil {
+		return notMocked("UnpauseAllContext")
+	}
+	return m.UnpauseAllContextFunc(ctx)
+}
+
+// UnpauseSlot calls UnpauseSlotFunc.
+func (m *MockClient) UnpauseSlot(slot int) error {
+	m.record("UnpauseSlot")
+	if m.UnpauseSlotFunc == nil {
+		return notMocked("UnpauseSlot")
+	}
+	return m.UnpauseSlotFunc(slot)
+}
+
+// UnpauseSlotContext calls UnpauseSlotContextFunc.
+func (m *MockClient) UnpauseSlotContext(ctx context.Context, slot int) error {
+	m.record("UnpauseSlotContext")
+	if m.UnpauseSlotContextFunc == nil {
+		return notMocked("UnpauseSlotContext")
+	}
+	return m.UnpauseSlotContextFunc(ctx, slot)
+}
+
+// UnpauseSlotVerified calls UnpauseSlotVerifiedFunc.
+func (m *MockClient) UnpauseSlotVerified(slot int, timeout time.Duration) error {
+	m.record("UnpauseSlotVerified")
+	if m.UnpauseSlotVerifiedFunc == nil {
+		return notMocked("UnpauseSlotVerified")
+	}
+	return m.UnpauseSlotVerifiedFunc(slot, timeout)
+}
+
+// UnpauseSlotVerifiedContext calls UnpauseSlotVerifiedContextFunc.
+func (m *MockClient) UnpauseSlotVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	m.record("UnpauseSlotVerifiedContext")
+	if m.UnpauseSlotVerifiedContextFunc == nil {
+		return notMocked("UnpauseSlotVerifiedContext")
+	}
+	return m.UnpauseSlotVerifiedContextFunc(ctx, slot, timeout)
+}
+
+// UpdatesAdd calls UpdatesAddFunc.
+func (m *MockClient) UpdatesAdd(id int, rate time.Duration, expression string) error {
+	m.record("UpdatesAdd")
+	if m.UpdatesAddFunc == nil {
+		return notMocked("UpdatesAdd")
+	}
+	return m.UpdatesAddFunc(id, rate, expression)
+}
+
+// UpdatesAddContext calls UpdatesAddContextFunc.
+func (m *MockClient) UpdatesAddContext(
+	ctx context.Context,
+	id int,
+	rate time.Duration,
+	expression string,
+) error {
+	m.record("UpdatesAddContext")
+	if m.UpdatesAddContextFunc == nil {
+		return notMocked("UpdatesAddContext")
+	}
+	return m.UpdatesAddContextFunc(ctx, id, rate, expression)
+}
+
+// UpdatesClear calls UpdatesClearFunc.
+func (m *MockClient) UpdatesClear() error {
+	m.record("UpdatesClear")
+	if m.UpdatesClearFunc == nil {
+		return notMocked("UpdatesClear")
+	}
+	return m.UpdatesClearFunc()
+}
+
+// UpdatesClearContext calls UpdatesClearContextFunc.
+func (m *MockClient) UpdatesClearContext(ctx context.Context) error {
+	m.record("UpdatesClearContext")
+	if m.UpdatesClearContextFunc == nil {
+		return notMocked("UpdatesClearContext")
+	}
+	return m.UpdatesClearContextFunc(ctx)
+}
+
+// UpdatesDel calls UpdatesDelFunc.
+func (m *MockClient) UpdatesDel(id int) error {
+	m.record("UpdatesDel")
+	if m.UpdatesDelFunc == nil {
+		return notMocked("UpdatesDel")
+	}
+	return m.UpdatesDelFunc(id)
+}
+
+// UpdatesDelContext calls UpdatesDelContextFunc.
+func (m *MockClient) UpdatesDelContext(ctx context.Context, id int) error {
+	m.record("UpdatesDelContext")
+	if m.UpdatesDelContextFunc == nil {
+		return notMocked("UpdatesDelContext")
+	}
+	return m.UpdatesDelContextFunc(ctx, id)
+}
+
+// UpdatesList calls UpdatesListFunc.
+func (m *MockClient) UpdatesList() (result string, err error) {
+	m.record("UpdatesList")
+	if m.UpdatesListFunc == nil {
+		return result, notMocked("UpdatesList")
+	}
+	return m.UpdatesListFunc()
+}
+
+// UpdatesListContext calls UpdatesListContextFunc.
+func (m *MockClient) UpdatesListContext(ctx context.Context) (result string, err error) {
+	m.record("UpdatesListContext")
+	if m.UpdatesListContextFunc == nil {
+		return result, notMocked("UpdatesListContext")
+	}
+	return m.UpdatesListContextFunc(ctx)
+}
+
+// UpdatesReset calls UpdatesResetFunc.
+func (m *MockClient) UpdatesReset() error {
+	m.record("UpdatesReset")
+	if m.UpdatesResetFunc == nil {
+		return notMocked("UpdatesReset")
+	}
+	return m.UpdatesResetFunc()
+}
+
+// UpdatesResetContext calls UpdatesResetContextFunc.
+func (m *MockClient) UpdatesResetContext(ctx context.Context) error {
+	m.record("UpdatesResetContext")
+	if m.UpdatesResetContextFunc == nil {
+		return notMocked("UpdatesResetContext")
+	}
+	return m.UpdatesResetContextFunc(ctx)
+}
+
+// Uptime calls UptimeFunc.
+func (m *MockClient) Uptime() (result fahapi.FAHDuration, err error) {
+	m.record("Uptime")
+	if m.UptimeFunc == nil {
+		return result, notMocked("Uptime")
+	}
+	return m.UptimeFunc()
+}
+
+// UptimeContext calls UptimeContextFunc.
+func (m *MockClient) UptimeContext(ctx context.Context) (result fahapi.FAHDuration, err error) {
+	m.record("UptimeContext")
+	if m.UptimeContextFunc == nil {
+		return result, notMocked("UptimeContext")
+	}
+	return m.UptimeContextFunc(ctx)
+}
+
+// WaitForUnits calls WaitForUnitsFunc.
+func (m *MockClient) WaitForUnits() error {
+	m.record("WaitForUnits")
+	if m.WaitForUnitsFunc == nil {
+		return notMocked("WaitForUnits")
+	}
+	return m.WaitForUnitsFunc()
+}
+
+// WaitForUnitsContext calls WaitForUnitsContextFunc.
+func (m *MockClient) WaitForUnitsContext(ctx context.Context) error {
+	m.record("WaitForUnitsContext")
+	if m.WaitForUnitsContextFunc == nil {
+		return notMocked("WaitForUnitsContext")
+	}
+	return m.WaitForUnitsContextFunc(ctx)
+}
+
+// WatchEvents calls WatchEventsFunc.
+func (m *MockClient) WatchEvents(
+	ctx context.Context,
+	rate time.Duration,
+) (result <-chan fahapi.WatchEvent, err error) {
+	m.record("WatchEvents")
+	if m.WatchEventsFunc == nil {
+		return result, notMocked("WatchEvents")
+	}
+	return m.WatchEventsFunc(ctx, rate)
+}
+
+// WatchOptions calls WatchOptionsFunc.
+func (m *MockClient) WatchOptions(
+	ctx context.Context,
+	rate time.Duration,
+) (result <-chan fahapi.Options, err error) {
+	m.record("WatchOptions")
+	if m.WatchOptionsFunc == nil {
+		return result, notMocked("WatchOptions")
+	}
+	return m.WatchOptionsFunc(ctx, rate)
+}
+
+// WatchQueueInfo calls WatchQueueInfoFunc.
+func (m *MockClient) WatchQueueInfo(
+	ctx context.Context,
+	rate time.Duration,
+) (result <-chan []fahapi.SlotQueueInfo, err error) {
+	m.record("WatchQueueInfo")
+	if m.WatchQueueInfoFunc == nil {
+		return result, notMocked("WatchQueueInfo")
+	}
+	return m.WatchQueueInfoFunc(ctx, rate)
+}
+
+// WatchSlotInfo calls WatchSlotInfoFunc.
+func (m *MockClient) WatchSlotInfo(
+	ctx context.Context,
+	rate time.Duration,
+) (result <-chan []fahapi.SlotInfo, err error) {
+	m.record("WatchSlotInfo")
+	if m.WatchSlotInfoFunc == nil {
+		return result, notMocked("WatchSlotInfo")
+	}
+	return m.WatchSlotInfoFunc(ctx, rate)
+}
@@ -0,0 +1,712 @@
+// Package fahtest provides a fake FAH client for testing code that uses fahapi without a running
+// FAHClient. The fake speaks the same telnet-style protocol and simulates slots, work units,
+// options and the log.
+package fahtest
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/MakotoE/go-fahapi"
+	"github.com/pkg/errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Welcome is the banner that is sent when a connection is accepted.
+const Welcome = "Welcome to the Folding@home Client command server."
+
+// ErrDisconnect can be returned by a HandlerFunc to close the connection without a response.
+var ErrDisconnect = errors.New("disconnect")
+
+// HandlerFunc returns the response to a command. args are the words after the command name, with
+// quotes removed. A returned error is sent as an "ERROR: " line.
+type HandlerFunc func(args []string) (string, error)
+
+// Server is a fake FAH client listening on a local TCP port. Its methods are goroutine-safe.
+type Server struct {
+	listener net.Listener
+	started  time.Time
+	wg       sync.WaitGroup
+
+	mutex    sync.Mutex // Guards the fields below
+	handlers map[string]HandlerFunc
+	options  map[string]string
+	slots    []*Slot
+	units    []fahapi.SlotQueueInfo
+	log      string
+	password string
+	commands []string
+	conns    map[*conn]struct{}
+}
+
+// Slot is a simulated slot.
+type Slot struct {
+	ID          int
+	Status      string // RUNNING, PAUSED or FINISHING
+	Description string
+	Options     map[string]string
+	Reason      string
+	Idle        bool
+}
+
+// slotDefaults are the default options of a slot.
+var slotDefaults = map[string]string{
+	"checkpoint":           "15",
+	"client-subtype":       "STDCLI",
+	"client-type":          "normal",
+	"core-priority":        "idle",
+	"cpu-usage":            "100",
+	"cpus":                 "-1",
+	"cuda-index":           "-1",
+	"gpu-index":            "",
+	"gpu-usage":            "100",
+	"idle":                 "false",
+	"machine-id":           "0",
+	"max-packet-size":      "normal",
+	"max-units":            "0",
+	"next-unit-percentage": "90",
+	"opencl-index":         "-1",
+	"pause-on-start":       "false",
+	"paused":               "false",
+}
+
+func (s *Slot) copy() Slot {
+	result := *s
+	result.Options = map[string]string{}
+	for key, value := range s.Options {
+		result.Options[key] = value
+	}
+	return result
+}
+
+func (s *Slot) setPaused(paused bool) {
+	s.Options["paused"] = strconv.FormatBool(paused)
+	if paused {
+		s.Status = "PAUSED"
+		s.Reason = "by user"
+	} else {
+		s.Status = "RUNNING"
+		s.Reason = ""
+	}
+}
+
+// NewServer starts a Server with one running CPU slot that is folding one work unit. It panics if
+// it cannot listen on a local port, like httptest.NewServer().
+func NewServer() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("fahtest: failed to listen: %s", err))
+	}
+
+	s := &Server{
+		listener: listener,
+		started:  time.Now(),
+		handlers: map[string]HandlerFunc{},
+		options:  defaultOptions(),
+		conns:    map[*conn]struct{}{},
+	}
+	s.AddSlot("cpu:4")
+	s.units = []fahapi.SlotQueueInfo{defaultUnit("00")}
+
+	s.wg.Add(1)
+	go s.accept()
+	return s
+}
+
+func defaultOptions() map[string]string {
+	return map[string]string{
+		"allow":                "127.0.0.1",
+		"cause":                "ANY",
+		"checkpoint":           "15",
+		"client-type":          "normal",
+		"command-port":         "36330",
+		"cpu-usage":            "100",
+		"cpus":                 "-1",
+		"fold-anon":            "false",
+		"gpu":                  "true",
+		"gpu-usage":            "100",
+		"idle":                 "false",
+		"log":                  "log.txt",
+		"machine-id":           "0",
+		"max-units":            "0",
+		"next-unit-percentage": "99",
+		"passkey":              "",
+		"password":             "",
+		"paused":               "false",
+		"power":                "medium",
+		"team":                 "0",
+		"user":                 "Anonymous",
+		"verbosity":            "3",
+	}
+}
+
+func defaultUnit(slot string) fahapi.SlotQueueInfo {
+	assigned, _ := fahapi.ParseFAHTime("2020-06-29T16:02:34Z")
+	timeout, _ := fahapi.ParseFAHTime("2020-06-30T16:02:34Z")
+	return fahapi.SlotQueueInfo{
+		ID:             "00",
+		State:          "RUNNING",
+		Error:          "NO_ERROR",
+		Project:        16435,
+		Run:            2466,
+		Clone:          2,
+		Gen:            37,
+		Core:           "0xa7",
+		Unit:           "0x0000002e80fccb0a5e7a7c22d3b8d0e2",
+		PercentDone:    "82.59%",
+		ETA:            fahapi.FAHDuration(time.Hour*2 + time.Minute*19),
+		PPD:            53463,
+		CreditEstimate: 21356,
+		TimeRemaining:  fahapi.FAHDuration(time.Hour * 24 * 8),
+		TotalFrames:    100,
+		FramesDone:     82,
+		Assigned:       assigned,
+		Timeout:        timeout,
+		Deadline:       timeout,
+		WS:             "128.252.203.10",
+		CS:             "0.0.0.0",
+		Slot:           slot,
+		TPF:            fahapi.FAHDuration(time.Minute*4 + time.Second*33),
+		BaseCredit:     9405,
+	}
+}
+
+// Addr returns the address that the server listens on, for fahapi.DialContext().
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops the server and closes all connections.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.DisconnectAll()
+	s.wg.Wait()
+	return errors.WithStack(err)
+}
+
+// DisconnectAll closes all connections as if the client was restarted. The server keeps accepting
+// new connections.
+func (s *Server) DisconnectAll() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for c := range s.conns {
+		c.Close()
+	}
+}
+
+// Handle makes the server respond to the command named name with handler. It overrides the
+// simulated command of the same name. A nil handler restores the simulated command.
+func (s *Server) Handle(name string, handler HandlerFunc) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if handler == nil {
+		delete(s.handlers, name)
+	} else {
+		s.handlers[name] = handler
+	}
+}
+
+// Commands returns the commands that were received, in order.
+func (s *Server) Commands() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]string(nil), s.commands...)
+}
+
+// SetPassword makes connections authenticate with the auth command before other commands. An
+// empty password disables authentication.
+func (s *Server) SetPassword(password string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.password = password
+}
+
+// Option returns the value of a client option.
+func (s *Server) Option(key string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.options[key]
+}
+
+// SetOption sets a client option.
+func (s *Server) SetOption(key string, value string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.options[key] = value
+}
+
+// AddSlot adds a running slot and returns its ID.
+func (s *Server) AddSlot(description string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.addSlot(description, map[string]string{})
+}
+
+func (s *Server) addSlot(description string, options map[string]string) int {
+	id := 0
+	for _, slot := range s.slots {
+		if slot.ID >= id {
+			id = slot.ID + 1
+		}
+	}
+
+	slot := &Slot{
+		ID:          id,
+		Status:      "RUNNING",
+		Description: description,
+		Options:     map[string]string{},
+	}
+	for key, value := range slotDefaults {
+		slot.Options[key] = value
+	}
+	for key, value := range options {
+		slot.Options[key] = value
+	}
+	s.slots = append(s.slots, slot)
+	return id
+}
+
+// Slots returns copies of the slots.
+func (s *Server) Slots() []Slot {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	result := make([]Slot, len(s.slots))
+	for i, slot := range s.slots {
+		result[i] = slot.copy()
+	}
+	return result
+}
+
+// Units returns the work units in the queue.
+func (s *Server) Units() []fahapi.SlotQueueInfo {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]fahapi.SlotQueueInfo(nil), s.units...)
+}
+
+// SetUnits replaces the work units in the queue.
+func (s *Server) SetUnits(units []fahapi.SlotQueueInfo) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.units = append([]fahapi.SlotQueueInfo(nil), units...)
+}
+
+// AppendLog appends text to the log and sends it to connections with log updates enabled. Lines
+// in text should end with a newline.
+func (s *Server) AppendLog(text string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.log += text
+	for c := range s.conns {
+		if c.logUpdates {
+			_ = c.push("log-update", text)
+		}
+	}
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+
+	for {
+		netConn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		c := &conn{Conn: netConn, server: s, updates: map[int]*update{}}
+		s.mutex.Lock()
+		s.conns[c] = struct{}{}
+		s.mutex.Unlock()
+
+		s.wg.Add(1)
+		go c.serve()
+	}
+}
+
+// exec runs a command that does not depend on the connection.
+func (s *Server) exec(name string, args []string) (string, error) {
+	s.mutex.Lock()
+	handler := s.handlers[name]
+	s.mutex.Unlock()
+
+	if handler == nil {
+		handler = s.builtin(name)
+	}
+
+	if handler == nil {
+		return "", errors.Errorf("unknown command or variable '%s'", name)
+	}
+
+	return handler(args)
+}
+
+// expand replaces $(command) and $command in text with the output of the command.
+func (s *Server) expand(text string) (string, error) {
+	var result strings.Builder
+	for {
+		i := strings.IndexByte(text, '$')
+		if i == -1 {
+			result.WriteString(text)
+			return result.String(), nil
+		}
+
+		result.WriteString(text[:i])
+		text = text[i+1:]
+
+		var command string
+		if strings.HasPrefix(text, "(") {
+			end := strings.IndexByte(text, ')')
+			if end == -1 {
+				return "", errors.New("missing )")
+			}
+			command, text = text[1:end], text[end+1:]
+		} else {
+			end := strings.IndexAny(text, " \\\"")
+			if end == -1 {
+				end = len(text)
+			}
+			command, text = text[:end], text[end:]
+		}
+
+		name, args, err := splitCommand(command)
+		if err != nil {
+			return "", err
+		}
+
+		output, err := s.exec(name, args)
+		if err != nil {
+			return "", err
+		}
+		result.WriteString(output)
+	}
+}
+
+// splitCommand splits a command line into words. Words can be quoted with double quotes, in which
+// \" and \\ are escapes.
+func splitCommand(line string) (string, []string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(line) && (line[i+1] == '"' || line[i+1] == '\\'):
+			i++
+			word.WriteByte(line[i])
+		case c == '"':
+			quoted = !quoted
+			inWord = true
+		case !quoted && c == ' ':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+
+	if quoted {
+		return "", nil, errors.New("unterminated quote")
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	if len(words) == 0 {
+		return "", nil, nil
+	}
+	return words[0], words[1:], nil
+}
+
+// conn is a connection from a client. authenticated and updates are only accessed by the goroutine
+// running serve().
+type conn struct {
+	net.Conn
+	server     *Server
+	writeMutex sync.Mutex
+
+	authenticated bool
+	updates       map[int]*update
+	logUpdates    bool // Guarded by server.mutex
+}
+
+func (c *conn) serve() {
+	defer c.server.wg.Done()
+	defer c.close()
+
+	if err := c.write(Welcome + "\n> "); err != nil {
+		return
+	}
+
+	scanner := bufio.NewScanner(c)
+	for scanner.Scan() {
+		command := strings.TrimSuffix(scanner.Text(), "\r")
+		if command == "" {
+			continue
+		}
+
+		c.server.mutex.Lock()
+		c.server.commands = append(c.server.commands, command)
+		c.server.mutex.Unlock()
+
+		if err := c.handle(command); err != nil {
+			return
+		}
+	}
+}
+
+func (c *conn) close() {
+	for id, u := range c.updates {
+		close(u.stop)
+		delete(c.updates, id)
+	}
+
+	c.server.mutex.Lock()
+	delete(c.server.conns, c)
+	c.server.mutex.Unlock()
+
+	c.Close()
+}
+
+// handle runs a command and writes the response. An error is returned if the connection should be
+// closed.
+func (c *conn) handle(command string) error {
+	name, args, err := splitCommand(command)
+	if err != nil {
+		return c.respond("", err)
+	}
+
+	switch name {
+	case "exit", "quit":
+		return ErrDisconnect
+	case "auth":
+		return c.respond(c.auth(args))
+	}
+
+	c.server.mutex.Lock()
+	password := c.server.password
+	c.server.mutex.Unlock()
+
+	if password != "" && !c.authenticated {
+		return c.respond("", errors.New("authentication required"))
+	}
+
+	switch name {
+	case "log-updates":
+		return c.logUpdatesCommand(args)
+	case "updates":
+		if err := c.respond(c.updatesCommand(args)); err != nil {
+			return err
+		}
+
+		// Results are sent after the response, like FAHClient
+		for _, u := range c.updates {
+			if !u.started {
+				u.started = true
+				c.server.wg.Add(1)
+				go c.runUpdate(u)
+			}
+		}
+		return nil
+	}
+
+	response, err := c.server.exec(name, args)
+	if err == ErrDisconnect {
+		return err
+	}
+	return c.respond(response, err)
+}
+
+func (c *conn) auth(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("auth requires a password")
+	}
+
+	c.server.mutex.Lock()
+	defer c.server.mutex.Unlock()
+
+	if args[0] != c.server.password {
+		return "", errors.New("invalid password")
+	}
+
+	c.authenticated = true
+	return "OK", nil
+}
+
+func (c *conn) logUpdatesCommand(args []string) error {
+	if len(args) != 1 {
+		return c.respond("", errors.New("log-updates requires start, restart or stop"))
+	}
+
+	c.server.mutex.Lock()
+	defer c.server.mutex.Unlock()
+
+	switch args[0] {
+	case "start", "restart":
+		c.logUpdates = true
+		// Like FAHClient, the log follows the prompt
+		if err := c.respond("", nil); err != nil {
+			return err
+		}
+		return c.push("log-update", c.server.log)
+	case "stop":
+		c.logUpdates = false
+		return c.respond("", nil)
+	}
+
+	return c.respond("", errors.Errorf("invalid log-updates argument: %s", args[0]))
+}
+
+// update is an expression that is evaluated periodically by the updates command.
+type update struct {
+	rate       int // Seconds
+	expression string
+	started    bool
+	stop       chan struct{}
+	reset      chan struct{}
+}
+
+func (c *conn) updatesCommand(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", errors.New("updates requires a subcommand")
+	}
+
+	switch args[0] {
+	case "add":
+		if len(args) != 4 {
+			return "", errors.New("usage: updates add <id> <rate> <expression>")
+		}
+
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		rate, err := strconv.Atoi(args[2])
+		if err != nil || rate < 1 {
+			return "", errors.Errorf("invalid rate: %s", args[2])
+		}
+
+		if u, ok := c.updates[id]; ok {
+			close(u.stop)
+		}
+
+		u := &update{
+			rate:       rate,
+			expression: args[3],
+			stop:       make(chan struct{}),
+			reset:      make(chan struct{}, 1),
+		}
+		c.updates[id] = u
+		return "", nil
+	case "del":
+		if len(args) != 2 {
+			return "", errors.New("usage: updates del <id>")
+		}
+
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		if u, ok := c.updates[id]; ok {
+			close(u.stop)
+			delete(c.updates, id)
+		}
+		return "", nil
+	case "list":
+		var lines []string
+		for id, u := range c.updates {
+			lines = append(lines, fmt.Sprintf("%d %d %s", id, u.rate, u.expression))
+		}
+		return strings.Join(lines, "\n"), nil
+	case "clear":
+		for id, u := range c.updates {
+			close(u.stop)
+			delete(c.updates, id)
+		}
+		return "", nil
+	case "reset":
+		for _, u := range c.updates {
+			select {
+			case u.reset <- struct{}{}:
+			default:
+			}
+		}
+		return "", nil
+	}
+
+	return "", errors.Errorf("invalid updates subcommand: %s", args[0])
+}
+
+// runUpdate sends the result of the update's expression immediately and then every u.rate.
+func (c *conn) runUpdate(u *update) {
+	defer c.server.wg.Done()
+
+	ticker := time.NewTicker(time.Duration(u.rate) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		result, err := c.server.expand(u.expression)
+		if err == nil {
+			if c.write("\n"+result+"\n") != nil {
+				return
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-u.reset:
+		case <-u.stop:
+			return
+		}
+	}
+}
+
+// respond writes a response followed by a prompt.
+func (c *conn) respond(response string, err error) error {
+	switch {
+	case err != nil:
+		return c.write("\nERROR: " + err.Error() + "\n> ")
+	case response == "":
+		return c.write("\n> ")
+	default:
+		return c.write("\n" + response + "\n> ")
+	}
+}
+
+// push writes a PyON message without a prompt.
+func (c *conn) push(name string, v interface{}) error {
+	message, err := fahapi.MarshalPyON(name, v)
+	if err != nil {
+		return err
+	}
+	return c.write("\n" + string(message) + "\n")
+}
+
+func (c *conn) write(s string) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	_, err := c.Write([]byte(s))
+	return errors.WithStack(err)
+}
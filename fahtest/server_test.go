@@ -0,0 +1,127 @@
+package fahtest
+
+import (
+	"bufio"
+	"github.com/MakotoE/checkerror"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		line         string
+		expectedName string
+		expectedArgs []string
+		expectError  bool
+	}{
+		{"", "", nil, false},
+		{"help", "help", []string{}, false},
+		{"  pause  0 ", "pause", []string{"0"}, false},
+		{`auth "a \"b\\"`, "auth", []string{`a "b\`}, false},
+		{`eval "$(uptime)\n"`, "eval", []string{`$(uptime)\n`}, false},
+		{`auth "a`, "", nil, true},
+	}
+
+	for i, test := range tests {
+		name, args, err := splitCommand(test.line)
+		assert.Equal(t, test.expectedName, name, i)
+		assert.Equal(t, test.expectedArgs, args, i)
+		checkerror.Check(t, test.expectError, err, i)
+	}
+}
+
+// readUntil reads from r until s is read and returns what was read.
+func readUntil(t *testing.T, r *bufio.Reader, s string) string {
+	var b strings.Builder
+	for !strings.HasSuffix(b.String(), s) {
+		c, err := r.ReadByte()
+		require.Nil(t, err, b.String())
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func TestServer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	server.Handle("a", func(args []string) (string, error) {
+		return strings.Join(args, ","), nil
+	})
+	server.Handle("b", func(args []string) (string, error) {
+		return "", errors.New("b")
+	})
+	server.Handle("c", func(args []string) (string, error) {
+		return "", ErrDisconnect
+	})
+
+	conn, err := net.Dial("tcp", server.Addr())
+	require.Nil(t, err)
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	assert.Equal(t, Welcome+"\n> ", readUntil(t, r, "> "))
+
+	tests := []struct {
+		command  string
+		expected string
+	}{
+		{"a 1 \"2 3\"", "\n1,2 3\n> "},
+		{"b", "\nERROR: b\n> "},
+		{"x", "\nERROR: unknown command or variable 'x'\n> "},
+		{"screensaver", "\n> "},
+		{`eval "$(a 1)$(a 2)\n"`, "\n12\\\n> "},
+		{"log-updates start", "\n> "},
+	}
+
+	for i, test := range tests {
+		_, err := conn.Write([]byte(test.command + "\n"))
+		require.Nil(t, err, i)
+		assert.Equal(t, test.expected, readUntil(t, r, "> "), i)
+	}
+
+	assert.Equal(t, "\nPyON 1 log-update\n\"\"\n---\n", readUntil(t, r, "---\n"))
+	server.AppendLog("a\n")
+	assert.Equal(t, "\nPyON 1 log-update\n\"a\\n\"\n---\n", readUntil(t, r, "---\n"))
+
+	_, err = conn.Write([]byte("c\n"))
+	require.Nil(t, err)
+	_, err = r.ReadByte()
+	assert.Equal(t, io.EOF, err)
+
+	assert.Equal(t, "a 1 \"2 3\"", server.Commands()[0])
+}
+
+func TestServer_password(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.SetPassword("a")
+
+	conn, err := net.Dial("tcp", server.Addr())
+	require.Nil(t, err)
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	readUntil(t, r, "> ")
+
+	tests := []struct {
+		command  string
+		expected string
+	}{
+		{"num-slots", "\nERROR: authentication required\n> "},
+		{`auth "b"`, "\nERROR: invalid password\n> "},
+		{`auth "a"`, "\nOK\n> "},
+		{"num-slots", "\nPyON 1 num-slots\n1\n---\n> "},
+	}
+
+	for i, test := range tests {
+		_, err := conn.Write([]byte(test.command + "\n"))
+		require.Nil(t, err, i)
+		assert.Equal(t, test.expected, readUntil(t, r, "> "), i)
+	}
+}
@@ -26,3 +26,12 @@ func Fuzz_parseLog(b []byte) int {
 
 	return 1
 }
+
+func Fuzz_UnmarshalPyON(b []byte) int {
+	var result interface{}
+	if err := UnmarshalPyON(b, &result); err != nil {
+		return 0
+	}
+
+	return 1
+}
@@ -4,7 +4,7 @@ go 1.13
 
 require (
 	github.com/MakotoE/checkerror v0.0.0-20190804021243-5a254e7ec556
-	github.com/json-iterator/go v1.1.10
+	github.com/json-iterator/go v1.1.12
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.5.1
 )
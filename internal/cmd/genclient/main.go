@@ -0,0 +1,514 @@
+// Command genclient generates the Client interface from the methods of API, and the
+// implementations that wrap another Client: the Pool methods, the interceptor of Intercept and
+// fahtest.MockClient. Run it with "go generate" in the root of the module.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Generated files, relative to the root of the module
+const (
+	clientOutput = "client_methods.go"
+	poolOutput   = "pool_methods.go"
+	mockOutput   = "fahtest/mock_client.go"
+)
+
+// maxLineLength is the line length of the repository.
+const maxLineLength = 100
+
+// notInClient are the methods of API that are not commands.
+var notInClient = map[string]bool{
+	"Close":   true,
+	"Session": true,
+}
+
+// notInPool are the methods of Client that Pool implements itself.
+var notInPool = map[string]bool{
+	"Auth":        true,
+	"AuthContext": true,
+}
+
+// connectionState are the methods that change the state of a connection. The Pool methods return
+// an error, because the state would apply to whichever connection is lent.
+var connectionState = map[string]bool{
+	"LogUpdates":          true,
+	"LogUpdatesContext":   true,
+	"UpdatesAdd":          true,
+	"UpdatesAddContext":   true,
+	"UpdatesClear":        true,
+	"UpdatesClearContext": true,
+	"UpdatesDel":          true,
+	"UpdatesDelContext":   true,
+}
+
+func main() {
+	files, err := generate(".")
+	if err != nil {
+		log.Fatalf("%+v", err)
+	}
+
+	for name, b := range files {
+		if err := ioutil.WriteFile(name, b, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+type param struct {
+	name          string
+	typeName      string
+	qualifiedType string // typeName with types of fahapi prefixed by "fahapi."
+	variadic      bool
+}
+
+type method struct {
+	receiver string // API or Connection
+	name     string
+	params   []param
+	results  []string // Types of the results; the last one is error
+	// qualifiedResults are the results with types of fahapi prefixed by "fahapi."
+	qualifiedResults []string
+}
+
+// generate returns the generated files for the package in dir, keyed by file name.
+func generate(dir string) (map[string][]byte, error) {
+	methods, err := clientMethods(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &bytes.Buffer{}
+	client.WriteString(`
+// Client has the command methods of API. It is implemented by API, Pool, the Client returned by
+// Intercept, and fahtest.MockClient.
+type Client interface {
+`)
+	for _, m := range methods {
+		fmt.Fprintf(client, "\t%s(%s) %s\n", m.name, m.paramList(false), m.resultList(false, false))
+	}
+	client.WriteString("}\n")
+
+	pool := &bytes.Buffer{}
+	mock := &bytes.Buffer{}
+	mock.WriteString(`
+// MockClient is a fahapi.Client for tests. Each method calls the field with the name of the method
+// and a Func suffix, or returns an error that matches ErrNotMocked if the field is nil. The names
+// of the called methods are recorded.
+type MockClient struct {
+`)
+	for _, m := range methods {
+		fmt.Fprintf(mock, "\t%sFunc func(%s) %s\n", m.name, m.paramList(true), m.resultList(true, false))
+	}
+	mock.WriteString(`
+	mutex sync.Mutex
+	calls []string
+}
+`)
+
+	names := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		names[m.name] = true
+	}
+
+	for _, m := range methods {
+		if err := m.check(); err != nil {
+			return nil, err
+		}
+
+		m.writeIntercepted(client, names[m.name+"Context"])
+		if !notInPool[m.name] {
+			m.writePool(pool)
+		}
+		m.writeMock(mock)
+	}
+
+	result := map[string][]byte{}
+	sources := []struct {
+		name        string
+		packageName string
+		body        *bytes.Buffer
+	}{
+		{clientOutput, "fahapi", client},
+		{poolOutput, "fahapi", pool},
+		{mockOutput, "fahtest", mock},
+	}
+	for _, source := range sources {
+		b, err := formatFile(source.packageName, source.body.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", source.name, err)
+		}
+		result[source.name] = b
+	}
+	return result, nil
+}
+
+// formatFile adds the header and imports to body.
+func formatFile(packageName string, body []byte) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("// Code generated by genclient from the methods of API; DO NOT EDIT.\n\n")
+	fmt.Fprintf(buffer, "package %s\n\nimport (\n", packageName)
+
+	imports := map[string]string{
+		"bytes.":   "bytes",
+		"context.": "context",
+		"fahapi.":  "github.com/MakotoE/go-fahapi",
+		"sync.":    "sync",
+		"time.":    "time",
+		"url.":     "net/url",
+	}
+	var paths []string
+	for prefix, path := range imports {
+		if bytes.Contains(body, []byte(prefix)) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(buffer, "\t%q\n", path)
+	}
+	buffer.WriteString(")\n")
+	buffer.Write(body)
+
+	return format.Source(buffer.Bytes())
+}
+
+// check returns an error if m cannot be wrapped.
+func (m method) check() error {
+	for _, p := range m.params {
+		switch p.name {
+		case "a", "c", "m", "p", "api", "err", "result":
+			return fmt.Errorf("%s: parameter name %s is reserved", m.name, p.name)
+		}
+	}
+
+	if len(m.results) != 1 && len(m.results) != 2 {
+		return fmt.Errorf("%s: unsupported number of results", m.name)
+	}
+	return nil
+}
+
+// paramList returns the parameters of m for a function signature.
+func (m method) paramList(qualified bool) string {
+	params := make([]string, len(m.params))
+	for i, p := range m.params {
+		typeName := p.typeName
+		if qualified {
+			typeName = p.qualifiedType
+		}
+
+		if p.variadic {
+			typeName = "..." + typeName
+		}
+		params[i] = p.name + " " + typeName
+	}
+	return strings.Join(params, ", ")
+}
+
+// resultList returns the results of m for a function signature. If named is true and there are
+// two results, they are named result and err.
+func (m method) resultList(qualified bool, named bool) string {
+	results := m.results
+	if qualified {
+		results = m.qualifiedResults
+	}
+
+	if len(results) == 1 {
+		return results[0]
+	} else if named {
+		return fmt.Sprintf("(result %s, err error)", results[0])
+	}
+	return fmt.Sprintf("(%s, error)", results[0])
+}
+
+// args returns the arguments that pass the parameters of m to another function.
+func (m method) args() string {
+	args := make([]string, len(m.params))
+	for i, p := range m.params {
+		args[i] = p.name
+		if p.variadic {
+			args[i] += "..."
+		}
+	}
+	return strings.Join(args, ", ")
+}
+
+// stream returns true if m returns a channel, which is used until the context argument is done.
+func (m method) stream() bool {
+	return len(m.results) == 2 && strings.HasPrefix(m.results[0], "<-chan ")
+}
+
+// ctx returns the context argument of m.
+func (m method) ctx() string {
+	if len(m.params) > 0 && m.params[0].typeName == "context.Context" {
+		return m.params[0].name
+	}
+	return "context.Background()"
+}
+
+// signature returns the declaration of m with the given receiver. Long parameter lists are split
+// into lines.
+func (m method) signature(receiver string, qualified bool) string {
+	params := m.paramList(qualified)
+	results := m.resultList(qualified, true)
+	signature := fmt.Sprintf("func (%s) %s(%s) %s {", receiver, m.name, params, results)
+	if len(signature) <= maxLineLength {
+		return signature
+	}
+
+	return fmt.Sprintf(
+		"func (%s) %s(\n\t%s,\n) %s {",
+		receiver,
+		m.name,
+		strings.Join(strings.Split(params, ", "), ",\n\t"),
+		results,
+	)
+}
+
+// writeComment writes text as a comment with lines of up to maxLineLength.
+func writeComment(w *bytes.Buffer, text string) {
+	line := "//"
+	for _, word := range strings.Fields(text) {
+		if len(line)+1+len(word) > maxLineLength {
+			fmt.Fprintf(w, "%s\n", line)
+			line = "//"
+		}
+		line += " " + word
+	}
+	fmt.Fprintf(w, "%s\n", line)
+}
+
+// writeWrapper writes a method that calls f with a function which makes call.
+func (m method) writeWrapper(w *bytes.Buffer, signature, f, call string) {
+	fmt.Fprintf(w, "%s\n", signature)
+	if len(m.results) == 1 {
+		fmt.Fprintf(w, "\treturn %s {\n\t\treturn %s\n\t})\n}\n", f, call)
+		return
+	}
+
+	fmt.Fprintf(w, "\terr = %s {\n", f)
+	fmt.Fprintf(w, "\t\tresult, err = %s\n\t\treturn err\n\t})\n\treturn\n}\n", call)
+}
+
+// writeIntercepted writes the method of interceptedClient. A method without a context argument
+// calls its Context variant if hasContext is true, so that it gets the ctx of the interceptor.
+// Methods that return a channel get the ctx of the caller, because the channel is used after the
+// interceptor returns.
+func (m method) writeIntercepted(w *bytes.Buffer, hasContext bool) {
+	ctxParam := "ctx context.Context"
+	call := fmt.Sprintf("c.next.%s(%s)", m.name, m.args())
+	if m.stream() {
+		ctxParam = "context.Context"
+	} else if m.ctx() == "context.Background()" && hasContext {
+		args := "ctx"
+		if len(m.params) > 0 {
+			args += ", " + m.args()
+		}
+		call = fmt.Sprintf("c.next.%sContext(%s)", m.name, args)
+	}
+
+	w.WriteString("\n")
+	m.writeWrapper(
+		w,
+		m.signature("c *interceptedClient", false),
+		fmt.Sprintf("c.intercept(%s, %q, func(%s) error", m.ctx(), m.name, ctxParam),
+		call,
+	)
+}
+
+func (m method) writePool(w *bytes.Buffer) {
+	w.WriteString("\n")
+	if connectionState[m.name] {
+		writeComment(w, fmt.Sprintf(
+			"%s returns an error because %s.%s changes the state of a connection. Use Do instead.",
+			m.name,
+			m.receiver,
+			m.name,
+		))
+		fmt.Fprintf(w, "%s\n", m.signature("p *Pool", false))
+		if len(m.results) == 1 {
+			fmt.Fprintf(w, "\treturn connectionStateError(%q)\n}\n", m.name)
+		} else {
+			fmt.Fprintf(w, "\treturn result, connectionStateError(%q)\n}\n", m.name)
+		}
+		return
+	}
+
+	f := "Do"
+	doc := fmt.Sprintf("%s is %s.%s with a connection from the pool.", m.name, m.receiver, m.name)
+	if m.stream() {
+		f = "lend"
+		doc = strings.TrimSuffix(doc, ".") + ", which is lent until ctx is done."
+	}
+	writeComment(w, doc)
+
+	m.writeWrapper(
+		w,
+		m.signature("p *Pool", false),
+		fmt.Sprintf("p.%s(%s, func(api *API) error", f, m.ctx()),
+		fmt.Sprintf("api.%s(%s)", m.name, m.args()),
+	)
+}
+
+func (m method) writeMock(w *bytes.Buffer) {
+	fmt.Fprintf(w, "\n// %s calls %sFunc.\n", m.name, m.name)
+	fmt.Fprintf(w, "%s\n", m.signature("m *MockClient", true))
+	fmt.Fprintf(w, "\tm.record(%q)\n", m.name)
+	fmt.Fprintf(w, "\tif m.%sFunc == nil {\n", m.name)
+	if len(m.results) == 1 {
+		fmt.Fprintf(w, "\t\treturn notMocked(%q)\n\t}\n", m.name)
+	} else {
+		fmt.Fprintf(w, "\t\treturn result, notMocked(%q)\n\t}\n", m.name)
+	}
+	fmt.Fprintf(w, "\treturn m.%sFunc(%s)\n}\n", m.name, m.args())
+}
+
+// clientMethods returns the exported methods of API and Connection in dir that are in Client,
+// sorted by name.
+func clientMethods(dir string) ([]method, error) {
+	generated := map[string]bool{}
+	for _, name := range []string{clientOutput, poolOutput, mockOutput} {
+		generated[filepath.Base(name)] = true
+	}
+
+	fileSet := token.NewFileSet()
+	filter := func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && !generated[info.Name()]
+	}
+
+	packages, err := parser.ParseDir(fileSet, dir, filter, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg, ok := packages["fahapi"]
+	if !ok {
+		return nil, fmt.Errorf("%s: package fahapi not found", dir)
+	}
+
+	var methods []method
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			f, ok := decl.(*ast.FuncDecl)
+			if !ok || f.Recv == nil || !f.Name.IsExported() || notInClient[f.Name.Name] {
+				continue
+			}
+
+			star, ok := f.Recv.List[0].Type.(*ast.StarExpr)
+			if !ok {
+				continue
+			}
+
+			receiver, ok := star.X.(*ast.Ident)
+			if !ok || (receiver.Name != "API" && receiver.Name != "Connection") {
+				continue
+			}
+
+			m, err := newMethod(fileSet, receiver.Name, f)
+			if err != nil {
+				return nil, err
+			}
+			methods = append(methods, m)
+		}
+	}
+
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].name < methods[j].name
+	})
+	return methods, nil
+}
+
+func newMethod(fileSet *token.FileSet, receiver string, f *ast.FuncDecl) (method, error) {
+	m := method{receiver: receiver, name: f.Name.Name}
+	for _, field := range f.Type.Params.List {
+		typeExpr := field.Type
+		ellipsis, variadic := typeExpr.(*ast.Ellipsis)
+		if variadic {
+			typeExpr = ellipsis.Elt
+		}
+
+		typeName, qualified, err := typeStrings(fileSet, typeExpr)
+		if err != nil {
+			return method{}, err
+		}
+
+		if len(field.Names) == 0 {
+			return method{}, fmt.Errorf("%s: unnamed parameter", m.name)
+		}
+
+		for _, name := range field.Names {
+			m.params = append(m.params, param{name.Name, typeName, qualified, variadic})
+		}
+	}
+
+	if f.Type.Results != nil {
+		for _, field := range f.Type.Results.List {
+			typeName, qualified, err := typeStrings(fileSet, field.Type)
+			if err != nil {
+				return method{}, err
+			}
+
+			for i := 0; i < max(1, len(field.Names)); i++ {
+				m.results = append(m.results, typeName)
+				m.qualifiedResults = append(m.qualifiedResults, qualified)
+			}
+		}
+	}
+
+	if len(m.results) == 0 || m.results[len(m.results)-1] != "error" {
+		return method{}, fmt.Errorf("%s: the last result is not an error", m.name)
+	}
+	return m, nil
+}
+
+// typeStrings returns the source of a type expression, and the source with the exported types of
+// fahapi prefixed by "fahapi.". The identifiers in expr are modified.
+func typeStrings(fileSet *token.FileSet, expr ast.Expr) (string, string, error) {
+	typeName, err := nodeString(fileSet, expr)
+	if err != nil {
+		return "", "", err
+	}
+
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			// Types of other packages
+			return false
+		case *ast.Ident:
+			if node.IsExported() {
+				node.Name = "fahapi." + node.Name
+			}
+		}
+		return true
+	})
+
+	qualified, err := nodeString(fileSet, expr)
+	return typeName, qualified, err
+}
+
+func nodeString(fileSet *token.FileSet, node ast.Node) (string, error) {
+	buffer := &bytes.Buffer{}
+	if err := printer.Fprint(buffer, fileSet, node); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
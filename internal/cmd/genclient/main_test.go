@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	files, err := generate("../../..")
+	require.Nil(t, err)
+	assert.Len(t, files, 3)
+
+	for name, result := range files {
+		expected, err := ioutil.ReadFile("../../../" + name)
+		require.Nil(t, err)
+		assert.Equal(t, string(expected), string(result), name+" is outdated; run go generate")
+	}
+}
@@ -0,0 +1,108 @@
+// Command genpatch generates OptionsPatch from the json tags of the Options struct. Run it with
+// "go generate" in the root of the module.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"strconv"
+)
+
+func main() {
+	b, err := generate("types.go")
+	if err != nil {
+		log.Fatalf("%+v", err)
+	}
+
+	if err := ioutil.WriteFile("options_patch.go", b, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type field struct {
+	name     string
+	typeName string
+	tag      string
+}
+
+// generate returns the source of options_patch.go for the Options struct in the given file.
+func generate(filename string) ([]byte, error) {
+	fields, err := optionsFields(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := &bytes.Buffer{}
+	buffer.WriteString(`// Code generated by genpatch from the Options struct; DO NOT EDIT.
+
+package fahapi
+
+// OptionsPatch contains changes to options for OptionsApply. Nil fields are not changed.
+type OptionsPatch struct {
+`)
+	for _, f := range fields {
+		fmt.Fprintf(buffer, "\t%s *%s\n", f.name, f.typeName)
+	}
+	buffer.WriteString(`}
+
+// changes returns the non-nil fields of p, keyed by option name.
+func (p *OptionsPatch) changes() OptionChanges {
+	changes := OptionChanges{}
+`)
+	for _, f := range fields {
+		fmt.Fprintf(buffer, "\tif p.%s != nil {\n\t\tchanges[%q] = *p.%s\n\t}\n", f.name, f.tag, f.name)
+	}
+	buffer.WriteString("\treturn changes\n}\n")
+
+	return format.Source(buffer.Bytes())
+}
+
+// optionsFields returns the fields of the Options struct in the given file.
+func optionsFields(filename string) ([]field, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), filename, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	object := file.Scope.Lookup("Options")
+	if object == nil {
+		return nil, fmt.Errorf("%s: Options not found", filename)
+	}
+
+	spec, ok := object.Decl.(*ast.TypeSpec)
+	if !ok {
+		return nil, fmt.Errorf("%s: Options is not a type", filename)
+	}
+
+	structType, ok := spec.Type.(*ast.StructType)
+	if !ok {
+		return nil, fmt.Errorf("%s: Options is not a struct", filename)
+	}
+
+	var fields []field
+	for _, f := range structType.Fields.List {
+		ident, ok := f.Type.(*ast.Ident)
+		if !ok || len(f.Names) != 1 || f.Tag == nil {
+			return nil, fmt.Errorf("%s: unsupported field in Options", filename)
+		}
+
+		tag, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, field{
+			name:     f.Names[0].Name,
+			typeName: ident.Name,
+			tag:      reflect.StructTag(tag).Get("json"),
+		})
+	}
+	return fields, nil
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	result, err := generate("../../../types.go")
+	require.Nil(t, err)
+
+	expected, err := ioutil.ReadFile("../../../options_patch.go")
+	require.Nil(t, err)
+	assert.Equal(t, string(expected), string(result), "options_patch.go is outdated; run go generate")
+}
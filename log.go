@@ -0,0 +1,115 @@
+package fahapi
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+)
+
+// LogLine is a line of the FAH client log without the trailing newline.
+type LogLine string
+
+// SubscribeLog enables log updates and returns a channel that receives each new log line. If log
+// updates were not already enabled by another subscriber, the channel first receives the whole log.
+// The channel is closed when ctx is done or the connection is lost. Log updates are disabled when
+// the last subscriber is done.
+func (a *API) SubscribeLog(ctx context.Context) (<-chan LogLine, error) {
+	return a.subscribeLog(ctx, false)
+}
+
+// subscribeLog is SubscribeLog. If skipLog is true, the whole log is not sent to the channel, so
+// that it only receives new lines.
+func (a *API) subscribeLog(ctx context.Context, skipLog bool) (<-chan LogLine, error) {
+	s := a.subscribe(logUpdateName)
+
+	// a.shared.mutex is locked before a.mutex
+	a.shared.mutex.Lock()
+	// The first log update after log updates are enabled is the whole log
+	skip := skipLog && a.shared.logSubscribers == 0
+	if a.shared.logSubscribers == 0 {
+		a.mutex.Lock()
+		err := a.ExecContext(ctx, "log-updates start", a.buffer)
+		a.mutex.Unlock()
+
+		if err != nil {
+			a.shared.mutex.Unlock()
+			a.unsubscribe(s)
+			return nil, err
+		}
+	}
+	a.shared.logSubscribers++
+	a.shared.mutex.Unlock()
+
+	lines := make(chan LogLine)
+	go func() {
+		defer close(lines)
+		defer a.unsubscribeLog(s)
+
+		splitter := logSplitter{}
+		for {
+			message, err := s.next(ctx)
+			if err != nil {
+				return
+			}
+
+			if skip {
+				skip = false
+				continue
+			}
+
+			chunk, err := parseLog(message)
+			if err != nil {
+				log.Printf("discarded invalid log update: %s", err)
+				continue
+			}
+
+			for _, line := range splitter.write(chunk) {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return lines, nil
+}
+
+func (a *API) unsubscribeLog(s *subscription) {
+	a.unsubscribe(s)
+
+	a.shared.mutex.Lock()
+	defer a.shared.mutex.Unlock()
+
+	a.shared.logSubscribers--
+	if a.shared.logSubscribers == 0 && !a.isClosed() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		a.mutex.Lock()
+		defer a.mutex.Unlock()
+
+		if err := a.ExecContext(ctx, "log-updates stop", a.buffer); err != nil {
+			log.Printf("failed to stop log updates: %s", err)
+		}
+	}
+}
+
+// logSplitter splits log updates into lines. An update can end in the middle of a line.
+type logSplitter struct {
+	partial string
+}
+
+// write returns the lines that were completed by chunk.
+func (l *logSplitter) write(chunk string) []LogLine {
+	split := strings.Split(l.partial+chunk, "\n")
+	l.partial = split[len(split)-1]
+
+	lines := make([]LogLine, 0, len(split)-1)
+	for _, line := range split[:len(split)-1] {
+		lines = append(lines, LogLine(strings.TrimSuffix(line, "\r")))
+	}
+	return lines
+}
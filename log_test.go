@@ -0,0 +1,126 @@
+package fahapi
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestLogSplitter(t *testing.T) {
+	splitter := logSplitter{}
+	assert.Empty(t, splitter.write(""))
+	assert.Empty(t, splitter.write("a"))
+	assert.Equal(t, []LogLine{"ab", ""}, splitter.write("b\r\n\n"))
+	assert.Equal(t, []LogLine{"c"}, splitter.write("c\nd"))
+	assert.Equal(t, "d", splitter.partial)
+}
+
+func TestAPI_SubscribeLog(t *testing.T) {
+	stopped := make(chan struct{})
+	listener := serveFake(t, func(command string) string {
+		switch command {
+		case "log-updates start":
+			return "\n> \nPyON 1 log-update\n\"a\\nb\"\n---\n"
+		case "log-updates stop":
+			close(stopped)
+		case "ppd":
+			return "\nPyON 1 log-update\n\"\\nc\\n\"\n---\n\nPyON 1 ppd\n1.5\n---\n> "
+		}
+		return "\n> "
+	})
+	defer listener.Close()
+
+	api, err := Dial(listener.Addr().(*net.TCPAddr))
+	require.Nil(t, err)
+	defer api.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines, err := api.SubscribeLog(ctx)
+	require.Nil(t, err)
+
+	// The log-update message before the response is not part of the response
+	ppd, err := api.PPD()
+	assert.Nil(t, err)
+	assert.Equal(t, 1.5, ppd)
+
+	assert.Equal(t, LogLine("a"), <-lines)
+	assert.Equal(t, LogLine("b"), <-lines)
+	assert.Equal(t, LogLine("c"), <-lines)
+
+	cancel()
+	_, ok := <-lines
+	assert.False(t, ok)
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Error("log updates were not stopped")
+	}
+}
+
+func TestAPI_LogUpdatesStop(t *testing.T) {
+	stops := make(chan struct{}, 2)
+	listener := serveFake(t, func(command string) string {
+		switch command {
+		case "log-updates start":
+			return "\n> \nPyON 1 log-update\n\"a\\n\"\n---\n"
+		case "log-updates stop":
+			stops <- struct{}{}
+		}
+		return "\n> "
+	})
+	defer listener.Close()
+
+	api, err := Dial(listener.Addr().(*net.TCPAddr))
+	require.Nil(t, err)
+	defer api.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines, err := api.SubscribeLog(ctx)
+	require.Nil(t, err)
+	assert.Equal(t, LogLine("a"), <-lines)
+
+	// Stopping would starve the subscriber
+	_, err = api.LogUpdates(LogUpdatesStop)
+	assert.NotNil(t, err)
+	assert.Empty(t, stops)
+
+	cancel()
+	for range lines {
+	}
+
+	select {
+	case <-stops:
+	case <-time.After(time.Second):
+		t.Fatal("log updates were not stopped")
+	}
+
+	_, err = api.LogUpdates(LogUpdatesStop)
+	assert.Nil(t, err)
+	assert.Len(t, stops, 1)
+}
+
+func TestAPI_LogUpdatesFake(t *testing.T) {
+	listener := serveFake(t, func(command string) string {
+		if command == "log-updates start" {
+			return "\n> \nPyON 1 log-update\n\"a\\x00\"\n---\n"
+		}
+		return command + "\n> "
+	})
+	defer listener.Close()
+
+	api, err := Dial(listener.Addr().(*net.TCPAddr))
+	require.Nil(t, err)
+	defer api.Close()
+
+	result, err := api.LogUpdates(LogUpdatesStart)
+	assert.Nil(t, err)
+	assert.Equal(t, "a\x00", result)
+
+	result, err = api.Help()
+	assert.Nil(t, err)
+	assert.Equal(t, "help", result)
+}
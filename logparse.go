@@ -0,0 +1,187 @@
+package fahapi
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogLevel is the level of a log line. Most lines have no level.
+type LogLevel string
+
+const (
+	LogLevelInfo    LogLevel = ""
+	LogLevelWarning LogLevel = "WARNING"
+	LogLevelError   LogLevel = "ERROR"
+)
+
+// LogEvent is a kind of log line that is recognized by ParseLogLine.
+type LogEvent int
+
+const (
+	LogEventNone       LogEvent = iota
+	LogEventDownload            // Downloading a WU, e.g. "Downloading 27.46MiB"
+	LogEventUpload              // Uploading a WU, e.g. "Sending unit results: ..."
+	LogEventCoreStart           // "Starting" or "Started FahCore on PID 123"
+	LogEventProgress            // "Completed 250000 out of 2500000 steps (10%)"
+	LogEventCheckpoint          // A line that mentions a checkpoint
+	LogEventBadState            // "Bad State detected"
+	LogEventFaulty              // "FahCore returned: FAULTY (88 = 0x58)"
+	LogEventCredit              // "Final credit estimate, 9405.00 points"
+)
+
+var logEventNames = [...]string{
+	"None",
+	"Download",
+	"Upload",
+	"CoreStart",
+	"Progress",
+	"Checkpoint",
+	"BadState",
+	"Faulty",
+	"Credit",
+}
+
+func (e LogEvent) String() string {
+	if e < 0 || int(e) >= len(logEventNames) {
+		return "LogEvent(" + strconv.Itoa(int(e)) + ")"
+	}
+	return logEventNames[e]
+}
+
+// LogRecord is a parsed log line.
+type LogRecord struct {
+	// Time of day of the line. LogParser sets the date from the date lines in the log; otherwise
+	// the date is January 1, year 0.
+	Time    time.Time
+	WU      int    // WU queue index, or -1
+	Slot    int    // Slot ID, or -1
+	Core    string // Core that printed the line, such as "0xa7"
+	Level   LogLevel
+	Message string
+	Event   LogEvent
+
+	// Set for LogEventProgress
+	StepsDone  int
+	TotalSteps int
+	Percent    int
+
+	// Set for LogEventCredit
+	Credit float64
+}
+
+var (
+	logProgressRegexp = regexp.MustCompile(`^Completed (\d+) out of (\d+) steps +\((\d+)%\)`)
+	logCreditRegexp   = regexp.MustCompile(`^Final credit estimate, ([\d.]+) points`)
+	logWURegexp       = regexp.MustCompile(`^WU(\d\d)$`)
+	logSlotRegexp     = regexp.MustCompile(`^FS(\d\d)$`)
+	logCoreRegexp     = regexp.MustCompile(`^0x[0-9a-fA-F]+$`)
+)
+
+// ParseLogLine parses a line like "12:34:56:WU01:FS00:0xa7:Completed 250000 out of 2500000 steps
+// (10%)". ok is false for lines without a time, such as the banners around the log header.
+func ParseLogLine(line LogLine) (record LogRecord, ok bool) {
+	s := string(line)
+	if len(s) < 9 || s[8] != ':' {
+		return LogRecord{}, false
+	}
+
+	t, err := time.Parse("15:04:05", s[:8])
+	if err != nil {
+		return LogRecord{}, false
+	}
+
+	record = LogRecord{Time: t, WU: -1, Slot: -1}
+	rest := s[9:]
+	for {
+		i := strings.IndexByte(rest, ':')
+		if i == -1 {
+			break
+		}
+
+		field := rest[:i]
+		if match := logWURegexp.FindStringSubmatch(field); match != nil && record.WU == -1 {
+			record.WU, _ = strconv.Atoi(match[1])
+		} else if match := logSlotRegexp.FindStringSubmatch(field); match != nil && record.Slot == -1 {
+			record.Slot, _ = strconv.Atoi(match[1])
+		} else if logCoreRegexp.MatchString(field) && record.Core == "" {
+			record.Core = field
+		} else if (field == "WARNING" || field == "ERROR") && record.Level == LogLevelInfo {
+			record.Level = LogLevel(field)
+		} else {
+			break
+		}
+		rest = rest[i+1:]
+	}
+
+	record.Message = rest
+	record.parseEvent()
+	return record, true
+}
+
+func (r *LogRecord) parseEvent() {
+	message := r.Message
+	lower := strings.ToLower(message)
+
+	if match := logProgressRegexp.FindStringSubmatch(message); match != nil {
+		r.Event = LogEventProgress
+		r.StepsDone, _ = strconv.Atoi(match[1])
+		r.TotalSteps, _ = strconv.Atoi(match[2])
+		r.Percent, _ = strconv.Atoi(match[3])
+	} else if match := logCreditRegexp.FindStringSubmatch(message); match != nil {
+		r.Event = LogEventCredit
+		r.Credit, _ = strconv.ParseFloat(match[1], 64)
+	} else if strings.Contains(message, "FAULTY") {
+		r.Event = LogEventFaulty
+	} else if strings.Contains(lower, "bad state") {
+		r.Event = LogEventBadState
+	} else if strings.Contains(lower, "checkpoint") {
+		r.Event = LogEventCheckpoint
+	} else if strings.HasPrefix(message, "Download") {
+		r.Event = LogEventDownload
+	} else if strings.HasPrefix(message, "Upload") ||
+		strings.HasPrefix(message, "Sending unit results") {
+		r.Event = LogEventUpload
+	} else if message == "Starting" || strings.HasPrefix(message, "Started FahCore") {
+		r.Event = LogEventCoreStart
+	}
+}
+
+// LogParser parses consecutive lines of a log, and keeps track of the date.
+type LogParser struct {
+	date time.Time // Midnight of the current day
+	last time.Time
+}
+
+var logDateRegexp = regexp.MustCompile(`(?:Date: |Log Started )(\d{4}-\d\d-\d\d)`)
+
+// Parse parses line like ParseLogLine, and sets the date of the record to the date of the last
+// date line, such as "*** Date: 2020-07-01 ***". If the time of a line is before the previous
+// line, the date is advanced by one day. Lines before the first date line have no date.
+func (p *LogParser) Parse(line LogLine) (LogRecord, bool) {
+	if match := logDateRegexp.FindStringSubmatch(string(line)); match != nil {
+		if date, err := time.Parse("2006-01-02", match[1]); err == nil {
+			p.date = date
+			p.last = time.Time{}
+		}
+	}
+
+	record, ok := ParseLogLine(line)
+	if !ok {
+		return record, false
+	}
+
+	if !p.date.IsZero() && !p.last.IsZero() && record.Time.Before(p.last) {
+		p.date = p.date.AddDate(0, 0, 1)
+	}
+	p.last = record.Time
+
+	if !p.date.IsZero() {
+		hour, min, sec := record.Time.Clock()
+		record.Time = time.Date(
+			p.date.Year(), p.date.Month(), p.date.Day(), hour, min, sec, 0, time.UTC,
+		)
+	}
+	return record, true
+}
@@ -0,0 +1,170 @@
+package fahapi
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseLogLine(t *testing.T) {
+	clock := func(hour, min, sec int) time.Time {
+		return time.Date(0, 1, 1, hour, min, sec, 0, time.UTC)
+	}
+
+	tests := []struct {
+		line       LogLine
+		expected   LogRecord
+		expectedOK bool
+	}{
+		{"", LogRecord{}, false},
+		{"******************************* Date: 2020-07-01 *******", LogRecord{}, false},
+		{"12:34:5a:a", LogRecord{}, false},
+		{
+			"12:34:56:Trying to access database...",
+			LogRecord{
+				Time:    clock(12, 34, 56),
+				WU:      -1,
+				Slot:    -1,
+				Message: "Trying to access database...",
+			},
+			true,
+		},
+		{
+			"12:34:56:WU01:FS00:0xa7:Completed 250000 out of 2500000 steps (10%)",
+			LogRecord{
+				Time:       clock(12, 34, 56),
+				WU:         1,
+				Slot:       0,
+				Core:       "0xa7",
+				Message:    "Completed 250000 out of 2500000 steps (10%)",
+				Event:      LogEventProgress,
+				StepsDone:  250000,
+				TotalSteps: 2500000,
+				Percent:    10,
+			},
+			true,
+		},
+		{
+			"00:00:01:WARNING:WU00:FS01:Connecting to 65.254.110.245:8080",
+			LogRecord{
+				Time:    clock(0, 0, 1),
+				WU:      0,
+				Slot:    1,
+				Level:   LogLevelWarning,
+				Message: "Connecting to 65.254.110.245:8080",
+			},
+			true,
+		},
+		{
+			"01:02:03:WU02:FS00:0x22:ERROR:Bad State detected... attempting to resume",
+			LogRecord{
+				Time:    clock(1, 2, 3),
+				WU:      2,
+				Slot:    0,
+				Core:    "0x22",
+				Level:   LogLevelError,
+				Message: "Bad State detected... attempting to resume",
+				Event:   LogEventBadState,
+			},
+			true,
+		},
+		{
+			"01:02:03:WU02:FS00:FahCore returned: FAULTY (88 = 0x58)",
+			LogRecord{
+				Time:    clock(1, 2, 3),
+				WU:      2,
+				Slot:    0,
+				Message: "FahCore returned: FAULTY (88 = 0x58)",
+				Event:   LogEventFaulty,
+			},
+			true,
+		},
+		{
+			"01:02:03:WU02:FS00:Final credit estimate, 9405.00 points",
+			LogRecord{
+				Time:    clock(1, 2, 3),
+				WU:      2,
+				Slot:    0,
+				Message: "Final credit estimate, 9405.00 points",
+				Event:   LogEventCredit,
+				Credit:  9405,
+			},
+			true,
+		},
+	}
+
+	for i, test := range tests {
+		record, ok := ParseLogLine(test.line)
+		assert.Equal(t, test.expected, record, i)
+		assert.Equal(t, test.expectedOK, ok, i)
+	}
+}
+
+func TestParseLogLine_events(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected LogEvent
+	}{
+		{"Downloading 27.46MiB", LogEventDownload},
+		{"Download complete", LogEventDownload},
+		{"Sending unit results: id:01 state:SEND error:NO_ERROR", LogEventUpload},
+		{"Upload 10.51%", LogEventUpload},
+		{"Starting", LogEventCoreStart},
+		{"Started FahCore on PID 1234", LogEventCoreStart},
+		{"Checkpoint completed at step 50000", LogEventCheckpoint},
+		{"Writing checkpoint files", LogEventCheckpoint},
+		{"Completed 0 out of 500000 steps  (0%)", LogEventProgress},
+		{"Server responded WORK_ACK (400)", LogEventNone},
+	}
+
+	for i, test := range tests {
+		record, ok := ParseLogLine(LogLine("12:00:00:WU00:FS00:" + test.message))
+		assert.True(t, ok, i)
+		assert.Equal(t, test.expected, record.Event, i)
+	}
+
+	assert.Equal(t, "Progress", LogEventProgress.String())
+	assert.Equal(t, "LogEvent(100)", LogEvent(100).String())
+}
+
+func TestLogParser(t *testing.T) {
+	tests := []struct {
+		lines    []LogLine
+		expected []time.Time
+	}{
+		{
+			[]LogLine{
+				"12:00:00:before date",
+				"*********************** Log Started 2020-06-30T23:59:00Z ***********************",
+				"23:59:00:a",
+				"00:00:30:b",
+				"******************************* Date: 2020-07-01 *******************************",
+				"00:01:00:c",
+			},
+			[]time.Time{
+				time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC),
+				time.Date(2020, 6, 30, 23, 59, 0, 0, time.UTC),
+				time.Date(2020, 7, 1, 0, 0, 30, 0, time.UTC),
+				time.Date(2020, 7, 1, 0, 1, 0, 0, time.UTC),
+			},
+		},
+		{
+			[]LogLine{"23:59:59:a", "00:00:01:b"},
+			[]time.Time{
+				time.Date(0, 1, 1, 23, 59, 59, 0, time.UTC),
+				time.Date(0, 1, 1, 0, 0, 1, 0, time.UTC),
+			},
+		},
+	}
+
+	for i, test := range tests {
+		parser := LogParser{}
+		var result []time.Time
+		for _, line := range test.lines {
+			if record, ok := parser.Parse(line); ok {
+				result = append(result, record.Time)
+			}
+		}
+		assert.Equal(t, test.expected, result, i)
+	}
+}
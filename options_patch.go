@@ -0,0 +1,501 @@
+// Code generated by genpatch from the Options struct; DO NOT EDIT.
+
+package fahapi
+
+// OptionsPatch contains changes to options for OptionsApply. Nil fields are not changed.
+type OptionsPatch struct {
+	Allow                  *string
+	CaptureDirectory       *string
+	CaptureOnError         *StringBool
+	CapturePackets         *StringBool
+	CaptureRequests        *StringBool
+	CaptureResponses       *StringBool
+	CaptureSockets         *StringBool
+	Cause                  *string
+	CertificateFile        *string
+	Checkpoint             *StringInt
+	Child                  *StringBool
+	ClientSubtype          *string
+	ClientThreads          *StringInt
+	ClientType             *string
+	CommandAddress         *string
+	CommandAllowNoPass     *string
+	Deny                   *string
+	CommandDenyNoPass      *string
+	CommandEnable          *StringBool
+	CommandPort            *StringInt
+	ConfigRotate           *StringBool
+	ConfigRotateDir        *string
+	ConfigRotateMax        *StringInt
+	ConnectionTimeout      *StringInt
+	CorePriority           *string
+	CpuSpecies             *string
+	CpuType                *string
+	CpuUsage               *StringInt
+	Cpus                   *StringInt
+	CrlFile                *string
+	CudaIndex              *string
+	CycleRate              *StringInt
+	Cycles                 *StringInt
+	Daemon                 *StringBool
+	DebugSockets           *StringBool
+	DisableSleepWhenActive *StringBool
+	DisableViz             *StringBool
+	DumpAfterDeadline      *StringBool
+	ExceptionLocations     *StringBool
+	ExitWhenDone           *StringBool
+	ExtraCoreArgs          *string
+	FoldAnon               *StringBool
+	Gpu                    *StringBool
+	GpuIndex               *string
+	GpuUsage               *StringInt
+	GuiEnabled             *StringBool
+	HttpAddresses          *string
+	HttpsAddresses         *string
+	Idle                   *StringBool
+	Log                    *string
+	LogColor               *StringBool
+	LogCrlf                *StringBool
+	LogDate                *StringBool
+	LogDatePeriodically    *StringInt
+	LogDomain              *StringBool
+	LogDomainLevels        *string
+	LogHeader              *StringBool
+	LogLevel               *StringBool
+	LogNoInfoHeader        *StringBool
+	LogRedirect            *StringBool
+	LogRotate              *StringBool
+	LogRotateDir           *string
+	LogRotateMax           *StringInt
+	LogShortLevel          *StringBool
+	LogSimpleDomains       *StringBool
+	LogThreadId            *StringBool
+	LogThreadPrefix        *StringBool
+	LogTime                *StringBool
+	LogToScreen            *StringBool
+	LogTruncate            *StringBool
+	MachineId              *StringInt
+	MaxConnectTime         *StringInt
+	MaxConnections         *StringInt
+	MaxPacketSize          *string
+	MaxQueue               *StringInt
+	MaxRequestLength       *StringInt
+	MaxShutdownWait        *StringInt
+	MaxSlotErrors          *StringInt
+	MaxUnitErrors          *StringInt
+	MaxUnits               *StringInt
+	Memory                 *string
+	MinConnectTime         *StringInt
+	NextUnitPercentage     *StringInt
+	Priority               *string
+	NoAssembly             *StringBool
+	OpenWebControl         *StringBool
+	OpenclIndex            *string
+	OsSpecies              *string
+	OsType                 *string
+	Passkey                *string
+	Password               *string
+	PauseOnBattery         *StringBool
+	PauseOnStart           *StringBool
+	Paused                 *StringBool
+	Pid                    *StringBool
+	PidFile                *string
+	Power                  *Power
+	PrivateKeyFile         *string
+	ProjectKey             *StringInt
+	Proxy                  *string
+	ProxyEnable            *StringBool
+	ProxyPass              *string
+	ProxyUser              *string
+	Respawn                *StringBool
+	Service                *StringBool
+	ServiceDescription     *string
+	ServiceRestart         *StringBool
+	ServiceRestartDelay    *StringInt
+	SessionCookie          *string
+	SessionLifetime        *StringInt
+	SessionTimeout         *StringInt
+	Smp                    *StringBool
+	StackTraces            *StringBool
+	StallDetectionEnabled  *StringBool
+	StallPercent           *StringInt
+	StallTimeout           *StringInt
+	Team                   *StringInt
+	User                   *string
+	Verbosity              *StringInt
+	WebAllow               *string
+	WebDeny                *string
+	WebEnable              *StringBool
+}
+
+// changes returns the non-nil fields of p, keyed by option name.
+func (p *OptionsPatch) changes() OptionChanges {
+	changes := OptionChanges{}
+	if p.Allow != nil {
+		changes["allow"] = *p.Allow
+	}
+	if p.CaptureDirectory != nil {
+		changes["capture-directory"] = *p.CaptureDirectory
+	}
+	if p.CaptureOnError != nil {
+		changes["capture-on-error"] = *p.CaptureOnError
+	}
+	if p.CapturePackets != nil {
+		changes["capture-packets"] = *p.CapturePackets
+	}
+	if p.CaptureRequests != nil {
+		changes["capture-requests"] = *p.CaptureRequests
+	}
+	if p.CaptureResponses != nil {
+		changes["capture-responses"] = *p.CaptureResponses
+	}
+	if p.CaptureSockets != nil {
+		changes["capture-sockets"] = *p.CaptureSockets
+	}
+	if p.Cause != nil {
+		changes["cause"] = *p.Cause
+	}
+	if p.CertificateFile != nil {
+		changes["certificate-file"] = *p.CertificateFile
+	}
+	if p.Checkpoint != nil {
+		changes["checkpoint"] = *p.Checkpoint
+	}
+	if p.Child != nil {
+		changes["child"] = *p.Child
+	}
+	if p.ClientSubtype != nil {
+		changes["client-subtype"] = *p.ClientSubtype
+	}
+	if p.ClientThreads != nil {
+		changes["client-threads"] = *p.ClientThreads
+	}
+	if p.ClientType != nil {
+		changes["client-type"] = *p.ClientType
+	}
+	if p.CommandAddress != nil {
+		changes["command-address"] = *p.CommandAddress
+	}
+	if p.CommandAllowNoPass != nil {
+		changes["command-allow-no-pass"] = *p.CommandAllowNoPass
+	}
+	if p.Deny != nil {
+		changes["deny"] = *p.Deny
+	}
+	if p.CommandDenyNoPass != nil {
+		changes["command-deny-no-pass"] = *p.CommandDenyNoPass
+	}
+	if p.CommandEnable != nil {
+		changes["command-enable"] = *p.CommandEnable
+	}
+	if p.CommandPort != nil {
+		changes["command-port"] = *p.CommandPort
+	}
+	if p.ConfigRotate != nil {
+		changes["config-rotate"] = *p.ConfigRotate
+	}
+	if p.ConfigRotateDir != nil {
+		changes["config-rotate-dir"] = *p.ConfigRotateDir
+	}
+	if p.ConfigRotateMax != nil {
+		changes["config-rotate-max"] = *p.ConfigRotateMax
+	}
+	if p.ConnectionTimeout != nil {
+		changes["connection-timeout"] = *p.ConnectionTimeout
+	}
+	if p.CorePriority != nil {
+		changes["core-priority"] = *p.CorePriority
+	}
+	if p.CpuSpecies != nil {
+		changes["cpu-species"] = *p.CpuSpecies
+	}
+	if p.CpuType != nil {
+		changes["cpu-type"] = *p.CpuType
+	}
+	if p.CpuUsage != nil {
+		changes["cpu-usage"] = *p.CpuUsage
+	}
+	if p.Cpus != nil {
+		changes["cpus"] = *p.Cpus
+	}
+	if p.CrlFile != nil {
+		changes["crl-file"] = *p.CrlFile
+	}
+	if p.CudaIndex != nil {
+		changes["cuda-index"] = *p.CudaIndex
+	}
+	if p.CycleRate != nil {
+		changes["cycle-rate"] = *p.CycleRate
+	}
+	if p.Cycles != nil {
+		changes["cycles"] = *p.Cycles
+	}
+	if p.Daemon != nil {
+		changes["daemon"] = *p.Daemon
+	}
+	if p.DebugSockets != nil {
+		changes["debug-sockets"] = *p.DebugSockets
+	}
+	if p.DisableSleepWhenActive != nil {
+		changes["disable-sleep-when-active"] = *p.DisableSleepWhenActive
+	}
+	if p.DisableViz != nil {
+		changes["disable-viz"] = *p.DisableViz
+	}
+	if p.DumpAfterDeadline != nil {
+		changes["dump-after-deadline"] = *p.DumpAfterDeadline
+	}
+	if p.ExceptionLocations != nil {
+		changes["exception-locations"] = *p.ExceptionLocations
+	}
+	if p.ExitWhenDone != nil {
+		changes["exit-when-done"] = *p.ExitWhenDone
+	}
+	if p.ExtraCoreArgs != nil {
+		changes["extra-core-args"] = *p.ExtraCoreArgs
+	}
+	if p.FoldAnon != nil {
+		changes["fold-anon"] = *p.FoldAnon
+	}
+	if p.Gpu != nil {
+		changes["gpu"] = *p.Gpu
+	}
+	if p.GpuIndex != nil {
+		changes["gpu-index"] = *p.GpuIndex
+	}
+	if p.GpuUsage != nil {
+		changes["gpu-usage"] = *p.GpuUsage
+	}
+	if p.GuiEnabled != nil {
+		changes["gui-enabled"] = *p.GuiEnabled
+	}
+	if p.HttpAddresses != nil {
+		changes["http-addresses"] = *p.HttpAddresses
+	}
+	if p.HttpsAddresses != nil {
+		changes["https-addresses"] = *p.HttpsAddresses
+	}
+	if p.Idle != nil {
+		changes["idle"] = *p.Idle
+	}
+	if p.Log != nil {
+		changes["log"] = *p.Log
+	}
+	if p.LogColor != nil {
+		changes["log-color"] = *p.LogColor
+	}
+	if p.LogCrlf != nil {
+		changes["log-crlf"] = *p.LogCrlf
+	}
+	if p.LogDate != nil {
+		changes["log-date"] = *p.LogDate
+	}
+	if p.LogDatePeriodically != nil {
+		changes["log-date-periodically"] = *p.LogDatePeriodically
+	}
+	if p.LogDomain != nil {
+		changes["log-domain"] = *p.LogDomain
+	}
+	if p.LogDomainLevels != nil {
+		changes["log-domain-levels"] = *p.LogDomainLevels
+	}
+	if p.LogHeader != nil {
+		changes["log-header"] = *p.LogHeader
+	}
+	if p.LogLevel != nil {
+		changes["log-level"] = *p.LogLevel
+	}
+	if p.LogNoInfoHeader != nil {
+		changes["log-no-info-header"] = *p.LogNoInfoHeader
+	}
+	if p.LogRedirect != nil {
+		changes["log-redirect"] = *p.LogRedirect
+	}
+	if p.LogRotate != nil {
+		changes["log-rotate"] = *p.LogRotate
+	}
+	if p.LogRotateDir != nil {
+		changes["log-rotate-dir"] = *p.LogRotateDir
+	}
+	if p.LogRotateMax != nil {
+		changes["log-rotate-max"] = *p.LogRotateMax
+	}
+	if p.LogShortLevel != nil {
+		changes["log-short-level"] = *p.LogShortLevel
+	}
+	if p.LogSimpleDomains != nil {
+		changes["log-simple-domains"] = *p.LogSimpleDomains
+	}
+	if p.LogThreadId != nil {
+		changes["log-thread-id"] = *p.LogThreadId
+	}
+	if p.LogThreadPrefix != nil {
+		changes["log-thread-prefix"] = *p.LogThreadPrefix
+	}
+	if p.LogTime != nil {
+		changes["log-time"] = *p.LogTime
+	}
+	if p.LogToScreen != nil {
+		changes["log-to-screen"] = *p.LogToScreen
+	}
+	if p.LogTruncate != nil {
+		changes["log-truncate"] = *p.LogTruncate
+	}
+	if p.MachineId != nil {
+		changes["machine-id"] = *p.MachineId
+	}
+	if p.MaxConnectTime != nil {
+		changes["max-connect-time"] = *p.MaxConnectTime
+	}
+	if p.MaxConnections != nil {
+		changes["max-connections"] = *p.MaxConnections
+	}
+	if p.MaxPacketSize != nil {
+		changes["max-packet-size"] = *p.MaxPacketSize
+	}
+	if p.MaxQueue != nil {
+		changes["max-queue"] = *p.MaxQueue
+	}
+	if p.MaxRequestLength != nil {
+		changes["max-request-length"] = *p.MaxRequestLength
+	}
+	if p.MaxShutdownWait != nil {
+		changes["max-shutdown-wait"] = *p.MaxShutdownWait
+	}
+	if p.MaxSlotErrors != nil {
+		changes["max-slot-errors"] = *p.MaxSlotErrors
+	}
+	if p.MaxUnitErrors != nil {
+		changes["max-unit-errors"] = *p.MaxUnitErrors
+	}
+	if p.MaxUnits != nil {
+		changes["max-units"] = *p.MaxUnits
+	}
+	if p.Memory != nil {
+		changes["memory"] = *p.Memory
+	}
+	if p.MinConnectTime != nil {
+		changes["min-connect-time"] = *p.MinConnectTime
+	}
+	if p.NextUnitPercentage != nil {
+		changes["next-unit-percentage"] = *p.NextUnitPercentage
+	}
+	if p.Priority != nil {
+		changes["priority"] = *p.Priority
+	}
+	if p.NoAssembly != nil {
+		changes["no-assembly"] = *p.NoAssembly
+	}
+	if p.OpenWebControl != nil {
+		changes["open-web-control"] = *p.OpenWebControl
+	}
+	if p.OpenclIndex != nil {
+		changes["opencl-index"] = *p.OpenclIndex
+	}
+	if p.OsSpecies != nil {
+		changes["os-species"] = *p.OsSpecies
+	}
+	if p.OsType != nil {
+		changes["os-type"] = *p.OsType
+	}
+	if p.Passkey != nil {
+		changes["passkey"] = *p.Passkey
+	}
+	if p.Password != nil {
+		changes["password"] = *p.Password
+	}
+	if p.PauseOnBattery != nil {
+		changes["pause-on-battery"] = *p.PauseOnBattery
+	}
+	if p.PauseOnStart != nil {
+		changes["pause-on-start"] = *p.PauseOnStart
+	}
+	if p.Paused != nil {
+		changes["paused"] = *p.Paused
+	}
+	if p.Pid != nil {
+		changes["pid"] = *p.Pid
+	}
+	if p.PidFile != nil {
+		changes["pid-file"] = *p.PidFile
+	}
+	if p.Power != nil {
+		changes["power"] = *p.Power
+	}
+	if p.PrivateKeyFile != nil {
+		changes["private-key-file"] = *p.PrivateKeyFile
+	}
+	if p.ProjectKey != nil {
+		changes["project-key"] = *p.ProjectKey
+	}
+	if p.Proxy != nil {
+		changes["proxy"] = *p.Proxy
+	}
+	if p.ProxyEnable != nil {
+		changes["proxy-enable"] = *p.ProxyEnable
+	}
+	if p.ProxyPass != nil {
+		changes["proxy-pass"] = *p.ProxyPass
+	}
+	if p.ProxyUser != nil {
+		changes["proxy-user"] = *p.ProxyUser
+	}
+	if p.Respawn != nil {
+		changes["respawn"] = *p.Respawn
+	}
+	if p.Service != nil {
+		changes["service"] = *p.Service
+	}
+	if p.ServiceDescription != nil {
+		changes["service-description"] = *p.ServiceDescription
+	}
+	if p.ServiceRestart != nil {
+		changes["service-restart"] = *p.ServiceRestart
+	}
+	if p.ServiceRestartDelay != nil {
+		changes["service-restart-delay"] = *p.ServiceRestartDelay
+	}
+	if p.SessionCookie != nil {
+		changes["session-cookie"] = *p.SessionCookie
+	}
+	if p.SessionLifetime != nil {
+		changes["session-lifetime"] = *p.SessionLifetime
+	}
+	if p.SessionTimeout != nil {
+		changes["session-timeout"] = *p.SessionTimeout
+	}
+	if p.Smp != nil {
+		changes["smp"] = *p.Smp
+	}
+	if p.StackTraces != nil {
+		changes["stack-traces"] = *p.StackTraces
+	}
+	if p.StallDetectionEnabled != nil {
+		changes["stall-detection-enabled"] = *p.StallDetectionEnabled
+	}
+	if p.StallPercent != nil {
+		changes["stall-percent"] = *p.StallPercent
+	}
+	if p.StallTimeout != nil {
+		changes["stall-timeout"] = *p.StallTimeout
+	}
+	if p.Team != nil {
+		changes["team"] = *p.Team
+	}
+	if p.User != nil {
+		changes["user"] = *p.User
+	}
+	if p.Verbosity != nil {
+		changes["verbosity"] = *p.Verbosity
+	}
+	if p.WebAllow != nil {
+		changes["web-allow"] = *p.WebAllow
+	}
+	if p.WebDeny != nil {
+		changes["web-deny"] = *p.WebDeny
+	}
+	if p.WebEnable != nil {
+		changes["web-enable"] = *p.WebEnable
+	}
+	return changes
+}
@@ -0,0 +1,187 @@
+package fahapi
+
+import (
+	"bytes"
+	"context"
+	"github.com/pkg/errors"
+	"net"
+	"sync"
+)
+
+// WithPipelining makes the methods of the connection goroutine-safe. Commands from different
+// goroutines are sent as they are made, without waiting for the responses to earlier commands, and
+// FAHClient responds to them in order. If ctx of a command is done, only that command returns;
+// its response is discarded when it arrives and the connection is kept. Use API.Session() to run
+// API methods concurrently.
+func WithPipelining() DialOption {
+	return func(c *Connection) {
+		c.pipelined = true
+	}
+}
+
+// writer sends the commands of a pipelined connection in its own goroutine.
+type writer struct {
+	conn     net.Conn
+	reader   *reader // Reader of conn; the writer stops with it
+	requests chan *request
+}
+
+// request is a command that is waiting to be sent by a writer.
+type request struct {
+	ctx      context.Context
+	command  string
+	response chan []byte // Receives the response from the reader
+	sent     bool        // Set before written receives a value
+	written  chan error  // Receives the result of writing the command
+}
+
+// startWriter must be called after c.Conn and c.reader are set.
+func startWriter(c *Connection) *writer {
+	w := &writer{
+		conn:     c.Conn,
+		reader:   c.reader,
+		requests: make(chan *request),
+	}
+
+	go w.run(c)
+	return w
+}
+
+func (w *writer) run(c *Connection) {
+	for {
+		select {
+		case req := <-w.requests:
+			// Commands that were cancelled while queued are not sent
+			if err := req.ctx.Err(); err != nil {
+				req.written <- errors.WithStack(err)
+				continue
+			}
+
+			w.reader.expect(req.response)
+			n, err := w.conn.Write(append([]byte(req.command), '\n'))
+			req.sent = n > 0
+			if err != nil {
+				err = errors.WithStack(err)
+				req.written <- err
+				c.readerFailed(w.reader, err)
+				w.conn.Close()
+				return
+			}
+			req.written <- nil
+		case <-w.reader.stopped:
+			return
+		}
+	}
+}
+
+// exec queues command and reads the response into buffer. sent is false if the command was not
+// written.
+func (w *writer) exec(
+	ctx context.Context,
+	command string,
+	buffer *bytes.Buffer,
+) (sent bool, err error) {
+	req := &request{
+		ctx:      ctx,
+		command:  command,
+		response: make(chan []byte, 1),
+		written:  make(chan error, 1),
+	}
+
+	select {
+	case w.requests <- req:
+	case <-w.reader.stopped:
+		return false, errors.New("connection lost")
+	case <-ctx.Done():
+		return false, errors.WithStack(ctx.Err())
+	}
+
+	select {
+	case err := <-req.written:
+		if err != nil {
+			return req.sent, err
+		}
+	case <-ctx.Done():
+		// The writer might still send the command
+		return true, errors.WithStack(ctx.Err())
+	}
+
+	b, err := w.reader.next(ctx, req.response)
+	buffer.Reset()
+	buffer.Write(b)
+	return true, err
+}
+
+// execPipelined is ExecContext for a pipelined connection.
+func (c *Connection) execPipelined(
+	ctx context.Context,
+	command string,
+	buffer *bytes.Buffer,
+) error {
+	r, w := c.current()
+	if w.reader != r || r.isStopped() {
+		// Another command lost the connection, or the last reconnection failed
+		if err := c.reconnectPipelined(ctx, r, nil); err != nil {
+			return &DisconnectError{Err: err}
+		}
+		r, w = c.current()
+	}
+
+	sent, err := w.exec(ctx, command, buffer)
+	if err == nil {
+		return checkResponse(command, buffer.Bytes())
+	}
+
+	if ctx.Err() != nil && errors.Cause(err) == ctx.Err() {
+		return err
+	}
+
+	return &DisconnectError{
+		Err:         err,
+		Sent:        sent,
+		Reconnected: c.reconnectPipelined(ctx, r, err) == nil,
+	}
+}
+
+func (c *Connection) current() (*reader, *writer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.reader, c.writer
+}
+
+// reconnectPipelined reconnects after the connection of failed was lost. If another command has
+// already reconnected, nil is returned. err is passed to the OnDisconnect function if it is not
+// nil.
+func (c *Connection) reconnectPipelined(ctx context.Context, failed *reader, err error) error {
+	c.reconnectMutex.Lock()
+	defer c.reconnectMutex.Unlock()
+
+	current, w := c.current()
+	if current != failed && w.reader == current && !current.isStopped() {
+		return nil
+	}
+
+	if err != nil && c.onDisconnect != nil {
+		c.onDisconnect(err)
+	}
+
+	return c.reconnect(ctx)
+}
+
+// Session returns an API that shares the connection of a but can run a command while a is running
+// one, if the connection was dialed WithPipelining(). Otherwise, the sessions take turns. Closing
+// any session closes the connection.
+func (a *API) Session() *API {
+	mutex := a.mutex
+	if a.pipelined {
+		mutex = &sync.Mutex{}
+	}
+
+	return &API{
+		Connection: a.Connection,
+		buffer:     &bytes.Buffer{},
+		mutex:      mutex,
+		shared:     a.shared,
+	}
+}
@@ -0,0 +1,249 @@
+package fahapi
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+// DefaultIdleCheck is the default Pool.IdleCheck.
+const DefaultIdleCheck = 10 * time.Second
+
+// Pool keeps up to a fixed number of connections to one client, and lends one to each call. It
+// has the methods of API, which can be called concurrently. Use Do to run several commands on the
+// same connection.
+//
+// Commands that change the state of a connection, like UpdatesAdd and LogUpdates, return an error
+// because they would apply to whichever connection is lent; run them with Do instead. The Watch
+// methods and SubscribeLog keep their connection lent until their ctx is done.
+type Pool struct {
+	// IdleCheck is the time that a connection can be idle before it is checked with a command
+	// when it is lent. Unhealthy connections are replaced. Set it before the pool is used.
+	IdleCheck time.Duration
+
+	addr    string
+	options []DialOption
+	tokens  chan struct{} // Has a value for each connection that can be lent
+
+	mutex    sync.Mutex // Guards the fields below
+	idle     []idleAPI  // The most recently used connection is last
+	password string     // Set by WithPassword() or Auth()
+	closed   bool
+}
+
+type idleAPI struct {
+	api   *API
+	since time.Time
+}
+
+// DialPool connects to the client at addr and returns a pool of up to size connections. If size is
+// zero or more than the max-connections option of the client, max-connections is used instead.
+// options are used for each connection, and other connections are dialed as needed.
+func DialPool(ctx context.Context, addr string, size int, options ...DialOption) (*Pool, error) {
+	api, err := DialContext(ctx, addr, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	clientOptions := &Options{}
+	if err := api.OptionsGetContext(ctx, clientOptions); err != nil {
+		api.Close()
+		return nil, err
+	}
+
+	if max := int(clientOptions.MaxConnections); max > 0 && (size <= 0 || size > max) {
+		size = max
+	}
+
+	if size <= 0 {
+		api.Close()
+		return nil, errors.Errorf("invalid pool size: %d", size)
+	}
+
+	p := &Pool{
+		IdleCheck: DefaultIdleCheck,
+		addr:      addr,
+		options:   options,
+		tokens:    make(chan struct{}, size),
+		idle:      []idleAPI{{api: api, since: time.Now()}},
+		password:  api.password,
+	}
+
+	for i := 0; i < size; i++ {
+		p.tokens <- struct{}{}
+	}
+
+	return p, nil
+}
+
+// Do runs f with a connection from the pool. It waits for a connection if all of them are lent,
+// until ctx is done. The connection must not be used after f returns.
+func (p *Pool) Do(ctx context.Context, f func(api *API) error) error {
+	api, err := p.take(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = f(api)
+	p.put(p.discardDisconnected(api, err), time.Now())
+	return err
+}
+
+// lend is Do for methods that return a channel which uses the connection. The connection stays
+// lent until ctx is done.
+func (p *Pool) lend(ctx context.Context, f func(api *API) error) error {
+	api, err := p.take(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := f(api); err != nil {
+		p.put(p.discardDisconnected(api, err), time.Now())
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		// The channel might have been closed because the connection was lost, so the connection is
+		// checked when it is lent again
+		p.put(api, time.Time{})
+	}()
+	return nil
+}
+
+// take waits for a token until ctx is done, and returns a connection.
+func (p *Pool) take(ctx context.Context) (*API, error) {
+	select {
+	case <-p.tokens:
+	case <-ctx.Done():
+		return nil, errors.WithStack(ctx.Err())
+	}
+
+	api, err := p.get(ctx)
+	if err != nil {
+		p.tokens <- struct{}{}
+		return nil, err
+	}
+	return api, nil
+}
+
+// discardDisconnected closes api and returns nil if err is a DisconnectError without a
+// reconnection. Otherwise api is returned.
+func (p *Pool) discardDisconnected(api *API, err error) *API {
+	var disconnectErr *DisconnectError
+	if errors.As(err, &disconnectErr) && !disconnectErr.Reconnected {
+		api.Close()
+		return nil
+	}
+	return api
+}
+
+// connectionStateError returns the error of the Pool methods that would change the state of the
+// lent connection.
+func connectionStateError(method string) error {
+	return errors.Errorf("Pool.%s changes the state of a connection; use Pool.Do", method)
+}
+
+// get returns an idle connection, or dials a new one.
+func (p *Pool) get(ctx context.Context) (*API, error) {
+	for {
+		p.mutex.Lock()
+		if p.closed {
+			p.mutex.Unlock()
+			return nil, errors.New("pool closed")
+		}
+
+		password := p.password
+		if len(p.idle) == 0 {
+			p.mutex.Unlock()
+
+			options := p.options
+			if password != "" {
+				options = append(options[:len(options):len(options)], WithPassword(password))
+			}
+			return DialContext(ctx, p.addr, options...)
+		}
+
+		idle := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		p.mutex.Unlock()
+
+		if err := p.check(ctx, idle, password); err != nil {
+			idle.api.Close()
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		return idle.api, nil
+	}
+}
+
+// check returns an error if the connection is not usable.
+func (p *Pool) check(ctx context.Context, idle idleAPI, password string) error {
+	if idle.api.password != password {
+		return idle.api.AuthContext(ctx, password)
+	}
+
+	if time.Since(idle.since) < p.IdleCheck {
+		return nil
+	}
+
+	_, err := idle.api.NumSlotsContext(ctx)
+	return err
+}
+
+// put returns a connection to the pool, which has been idle since the given time. api is nil if the
+// connection was closed.
+func (p *Pool) put(api *API, since time.Time) {
+	if api != nil {
+		p.mutex.Lock()
+		if p.closed {
+			api.Close()
+		} else {
+			p.idle = append(p.idle, idleAPI{api: api, since: since})
+		}
+		p.mutex.Unlock()
+	}
+
+	p.tokens <- struct{}{}
+}
+
+// Auth authenticates every connection of the pool with the client's password.
+func (p *Pool) Auth(password string) error {
+	return p.AuthContext(context.Background(), password)
+}
+
+// AuthContext is Auth with a context.
+func (p *Pool) AuthContext(ctx context.Context, password string) error {
+	return p.Do(ctx, func(api *API) error {
+		if err := api.AuthContext(ctx, password); err != nil {
+			return err
+		}
+
+		// Idle connections authenticate when they are lent
+		p.mutex.Lock()
+		p.password = password
+		p.mutex.Unlock()
+		return nil
+	})
+}
+
+// Close closes the idle connections. Lent connections are closed when they are returned.
+func (p *Pool) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.closed = true
+
+	var result error
+	for _, idle := range p.idle {
+		if err := idle.api.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	p.idle = nil
+	return result
+}
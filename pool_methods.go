@@ -0,0 +1,1012 @@
+// Code generated by genclient from the methods of API; DO NOT EDIT.
+
+package fahapi
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"time"
+)
+
+// AlwaysOn is API.AlwaysOn with a connection from the pool.
+func (p *Pool) AlwaysOn(slot int) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.AlwaysOn(slot)
+	})
+}
+
+// AlwaysOnContext is API.AlwaysOnContext with a connection from the pool.
+func (p *Pool) AlwaysOnContext(ctx context.Context, slot int) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.AlwaysOnContext(ctx, slot)
+	})
+}
+
+// AlwaysOnVerified is API.AlwaysOnVerified with a connection from the pool.
+func (p *Pool) AlwaysOnVerified(slot int, timeout time.Duration) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.AlwaysOnVerified(slot, timeout)
+	})
+}
+
+// AlwaysOnVerifiedContext is API.AlwaysOnVerifiedContext with a connection from the pool.
+func (p *Pool) AlwaysOnVerifiedContext(ctx context.Context, slot int, timeout time.Duration) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.AlwaysOnVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+// Configured is API.Configured with a connection from the pool.
+func (p *Pool) Configured() (result bool, err error) {
+	err = p.Do(context.Background(), func(api *API) error {
+		result, err = api.Configured()
+		return err
+	})
+	return
+}
+
+// ConfiguredContext is API.ConfiguredContext with a connection from the pool.
+func (p *Pool) ConfiguredContext(ctx context.Context) (result bool, err error) {
+	err = p.Do(ctx, func(api *API) error {
+		result, err = api.ConfiguredContext(ctx)
+		return err
+	})
+	return
+}
+
+// Diff is API.Diff with a connection from the pool.
+func (p *Pool) Diff(config *Config) (result *Diff, err error) {
+	err = p.Do(context.Background(), func(api *API) error {
+		result, err = api.Diff(config)
+		return err
+	})
+	return
+}
+
+// DiffContext is API.DiffContext with a connection from the pool.
+func (p *Pool) DiffContext(ctx context.Context, config *Config) (result *Diff, err error) {
+	err = p.Do(ctx, func(api *API) error {
+		result, err = api.DiffContext(ctx, config)
+		return err
+	})
+	return
+}
+
+// DoCycle is API.DoCycle with a connection from the pool.
+func (p *Pool) DoCycle() error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.DoCycle()
+	})
+}
+
+// DoCycleContext is API.DoCycleContext with a connection from the pool.
+func (p *Pool) DoCycleContext(ctx context.Context) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.DoCycleContext(ctx)
+	})
+}
+
+// DownloadCore is API.DownloadCore with a connection from the pool.
+func (p *Pool) DownloadCore(coreType string, url *url.URL) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.DownloadCore(coreType, url)
+	})
+}
+
+// DownloadCoreContext is API.DownloadCoreContext with a connection from the pool.
+func (p *Pool) DownloadCoreContext(ctx context.Context, coreType string, url *url.URL) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.DownloadCoreContext(ctx, coreType, url)
+	})
+}
+
+// Exec is Connection.Exec with a connection from the pool.
+func (p *Pool) Exec(command string, buffer *bytes.Buffer) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.Exec(command, buffer)
+	})
+}
+
+// ExecContext is Connection.ExecContext with a connection from the pool.
+func (p *Pool) ExecContext(ctx context.Context, command string, buffer *bytes.Buffer) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.ExecContext(ctx, command, buffer)
+	})
+}
+
+// ExecEval is Connection.ExecEval with a connection from the pool.
+func (p *Pool) ExecEval(command string, buffer *bytes.Buffer) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.ExecEval(command, buffer)
+	})
+}
+
+// ExecEvalContext is Connection.ExecEvalContext with a connection from the pool.
+func (p *Pool) ExecEvalContext(ctx context.Context, command string, buffer *bytes.Buffer) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.ExecEvalContext(ctx, command, buffer)
+	})
+}
+
+// ExportConfig is API.ExportConfig with a connection from the pool.
+func (p *Pool) ExportConfig() (result *Config, err error) {
+	err = p.Do(context.Background(), func(api *API) error {
+		result, err = api.ExportConfig()
+		return err
+	})
+	return
+}
+
+// ExportConfigContext is API.ExportConfigContext with a connection from the pool.
+func (p *Pool) ExportConfigContext(ctx context.Context) (result *Config, err error) {
+	err = p.Do(ctx, func(api *API) error {
+		result, err = api.ExportConfigContext(ctx)
+		return err
+	})
+	return
+}
+
+// Finish is API.Finish with a connection from the pool.
+func (p *Pool) Finish(slot int) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.Finish(slot)
+	})
+}
+
+// FinishAll is API.FinishAll with a connection from the pool.
+func (p *Pool) FinishAll() error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.FinishAll()
+	})
+}
+
+// FinishAllContext is API.FinishAllContext with a connection from the pool.
+func (p *Pool) FinishAllContext(ctx context.Context) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.FinishAllContext(ctx)
+	})
+}
+
+// FinishSlot is API.FinishSlot with a connection from the pool.
+func (p *Pool) FinishSlot(slot int) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.FinishSlot(slot)
+	})
+}
+
+// FinishSlotContext is API.FinishSlotContext with a connection from the pool.
+func (p *Pool) FinishSlotContext(ctx context.Context, slot int) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.FinishSlotContext(ctx, slot)
+	})
+}
+
+// FinishSlotVerified is API.FinishSlotVerified with a connection from the pool.
+func (p *Pool) FinishSlotVerified(slot int, timeout time.Duration) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.FinishSlotVerified(slot, timeout)
+	})
+}
+
+// FinishSlotVerifiedContext is API.FinishSlotVerifiedContext with a connection from the pool.
+func (p *Pool) FinishSlotVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.FinishSlotVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+// Help is API.Help with a connection from the pool.
+func (p *Pool) Help() (result string, err error) {
+	err = p.Do(context.Background(), func(api *API) error {
+		result, err = api.Help()
+		return err
+	})
+	return
+}
+
+// HelpContext is API.HelpContext with a connection from the pool.
+func (p *Pool) HelpContext(ctx context.Context) (result string, err error) {
+	err = p.Do(ctx, func(api *API) error {
+		result, err = api.HelpContext(ctx)
+		return err
+	})
+	return
+}
+
+// ImportConfig is API.ImportConfig with a connection from the pool.
+func (p *Pool) ImportConfig(config *Config, dryRun bool) (result *Diff, err error) {
+	err = p.Do(context.Background(), func(api *API) error {
+		result, err = api.ImportConfig(config, dryRun)
+		return err
+	})
+	return
+}
+
+// ImportConfigContext is API.ImportConfigContext with a connection from the pool.
+func (p *Pool) ImportConfigContext(
+	ctx context.Context,
+	config *Config,
+	dryRun bool,
+) (result *Diff, err error) {
+	err = p.Do(ctx, func(api *API) error {
+		result, err = api.ImportConfigContext(ctx, config, dryRun)
+		return err
+	})
+	return
+}
+
+// Info is API.Info with a connection from the pool.
+func (p *Pool) Info() (result [][]interface{}, err error) {
+	err = p.Do(context.Background(), func(api *API) error {
+		result, err = api.Info()
+		return err
+	})
+	return
+}
+
+// InfoContext is API.InfoContext with a connection from the pool.
+func (p *Pool) InfoContext(ctx context.Context) (result [][]interface{}, err error) {
+	err = p.Do(ctx, func(api *API) error {
+		result, err = api.InfoContext(ctx)
+		return err
+	})
+	return
+}
+
+// InfoStruct is API.InfoStruct with a connection from the pool.
+func (p *Pool) InfoStruct(dst *Info) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.InfoStruct(dst)
+	})
+}
+
+// InfoStructContext is API.InfoStructContext with a connection from the pool.
+func (p *Pool) InfoStructContext(ctx context.Context, dst *Info) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.InfoStructContext(ctx, dst)
+	})
+}
+
+// LogUpdates returns an error because API.LogUpdates changes the state of a connection. Use Do
+// instead.
+func (p *Pool) LogUpdates(arg LogUpdatesArg) (result string, err error) {
+	return result, connectionStateError("LogUpdates")
+}
+
+// LogUpdatesContext returns an error because API.LogUpdatesContext changes the state of a
+// connection. Use Do instead.
+func (p *Pool) LogUpdatesContext(
+	ctx context.Context,
+	arg LogUpdatesArg,
+) (result string, err error) {
+	return result, connectionStateError("LogUpdatesContext")
+}
+
+// NumSlots is API.NumSlots with a connection from the pool.
+func (p *Pool) NumSlots() (result int, err error) {
+	err = p.Do(context.Background(), func(api *API) error {
+		result, err = api.NumSlots()
+		return err
+	})
+	return
+}
+
+// NumSlotsContext is API.NumSlotsContext with a connection from the pool.
+func (p *Pool) NumSlotsContext(ctx context.Context) (result int, err error) {
+	err = p.Do(ctx, func(api *API) error {
+		result, err = api.NumSlotsContext(ctx)
+		return err
+	})
+	return
+}
+
+// OnIdle is API.OnIdle with a connection from the pool.
+func (p *Pool) OnIdle(slot int) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.OnIdle(slot)
+	})
+}
+
+// OnIdleAll is API.OnIdleAll with a connection from the pool.
+func (p *Pool) OnIdleAll() error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.OnIdleAll()
+	})
+}
+
+// OnIdleAllContext is API.OnIdleAllContext with a connection from the pool.
+func (p *Pool) OnIdleAllContext(ctx context.Context) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.OnIdleAllContext(ctx)
+	})
+}
+
+// OnIdleContext is API.OnIdleContext with a connection from the pool.
+func (p *Pool) OnIdleContext(ctx context.Context, slot int) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.OnIdleContext(ctx, slot)
+	})
+}
+
+// OnIdleVerified is API.OnIdleVerified with a connection from the pool.
+func (p *Pool) OnIdleVerified(slot int, timeout time.Duration) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.OnIdleVerified(slot, timeout)
+	})
+}
+
+// OnIdleVerifiedContext is API.OnIdleVerifiedContext with a connection from the pool.
+func (p *Pool) OnIdleVerifiedContext(ctx context.Context, slot int, timeout time.Duration) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.OnIdleVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+// OptionsApply is API.OptionsApply with a connection from the pool.
+func (p *Pool) OptionsApply(patch *OptionsPatch, dst *Options) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.OptionsApply(patch, dst)
+	})
+}
+
+// OptionsApplyContext is API.OptionsApplyContext with a connection from the pool.
+func (p *Pool) OptionsApplyContext(ctx context.Context, patch *OptionsPatch, dst *Options) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.OptionsApplyContext(ctx, patch, dst)
+	})
+}
+
+// OptionsChanged is API.OptionsChanged with a connection from the pool.
+func (p *Pool) OptionsChanged() (result map[string]string, err error) {
+	err = p.Do(context.Background(), func(api *API) error {
+		result, err = api.OptionsChanged()
+		return err
+	})
+	return
+}
+
+// OptionsChangedContext is API.OptionsChangedContext with a connection from the pool.
+func (p *Pool) OptionsChangedContext(ctx context.Context) (result map[string]string, err error) {
+	err = p.Do(ctx, func(api *API) error {
+		result, err = api.OptionsChangedContext(ctx)
+		return err
+	})
+	return
+}
+
+// OptionsGet is API.OptionsGet with a connection from the pool.
+func (p *Pool) OptionsGet(dst *Options) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.OptionsGet(dst)
+	})
+}
+
+// OptionsGetContext is API.OptionsGetContext with a connection from the pool.
+func (p *Pool) OptionsGetContext(ctx context.Context, dst *Options) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.OptionsGetContext(ctx, dst)
+	})
+}
+
+// OptionsReset is API.OptionsReset with a connection from the pool.
+func (p *Pool) OptionsReset(dst *Options, keys ...string) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.OptionsReset(dst, keys...)
+	})
+}
+
+// OptionsResetContext is API.OptionsResetContext with a connection from the pool.
+func (p *Pool) OptionsResetContext(ctx context.Context, dst *Options, keys ...string) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.OptionsResetContext(ctx, dst, keys...)
+	})
+}
+
+// OptionsSet is API.OptionsSet with a connection from the pool.
+func (p *Pool) OptionsSet(key string, value interface{}) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.OptionsSet(key, value)
+	})
+}
+
+// OptionsSetContext is API.OptionsSetContext with a connection from the pool.
+func (p *Pool) OptionsSetContext(ctx context.Context, key string, value interface{}) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.OptionsSetContext(ctx, key, value)
+	})
+}
+
+// OptionsUpdate is API.OptionsUpdate with a connection from the pool.
+func (p *Pool) OptionsUpdate(changes OptionChanges, dst *Options) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.OptionsUpdate(changes, dst)
+	})
+}
+
+// OptionsUpdateContext is API.OptionsUpdateContext with a connection from the pool.
+func (p *Pool) OptionsUpdateContext(
+	ctx context.Context,
+	changes OptionChanges,
+	dst *Options,
+) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.OptionsUpdateContext(ctx, changes, dst)
+	})
+}
+
+// OptionsWithDefaults is API.OptionsWithDefaults with a connection from the pool.
+func (p *Pool) OptionsWithDefaults() (result map[string]string, err error) {
+	err = p.Do(context.Background(), func(api *API) error {
+		result, err = api.OptionsWithDefaults()
+		return err
+	})
+	return
+}
+
+// OptionsWithDefaultsContext is API.OptionsWithDefaultsContext with a connection from the pool.
+func (p *Pool) OptionsWithDefaultsContext(
+	ctx context.Context,
+) (result map[string]string, err error) {
+	err = p.Do(ctx, func(api *API) error {
+		result, err = api.OptionsWithDefaultsContext(ctx)
+		return err
+	})
+	return
+}
+
+// PPD is API.PPD with a connection from the pool.
+func (p *Pool) PPD() (result float64, err error) {
+	err = p.Do(context.Background(), func(api *API) error {
+		result, err = api.PPD()
+		return err
+	})
+	return
+}
+
+// PPDContext is API.PPDContext with a connection from the pool.
+func (p *Pool) PPDContext(ctx context.Context) (result float64, err error) {
+	err = p.Do(ctx, func(api *API) error {
+		result, err = api.PPDContext(ctx)
+		return err
+	})
+	return
+}
+
+// PauseAll is API.PauseAll with a connection from the pool.
+func (p *Pool) PauseAll() error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.PauseAll()
+	})
+}
+
+// PauseAllContext is API.PauseAllContext with a connection from the pool.
+func (p *Pool) PauseAllContext(ctx context.Context) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.PauseAllContext(ctx)
+	})
+}
+
+// PauseSlot is API.PauseSlot with a connection from the pool.
+func (p *Pool) PauseSlot(slot int) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.PauseSlot(slot)
+	})
+}
+
+// PauseSlotContext is API.PauseSlotContext with a connection from the pool.
+func (p *Pool) PauseSlotContext(ctx context.Context, slot int) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.PauseSlotContext(ctx, slot)
+	})
+}
+
+// PauseSlotVerified is API.PauseSlotVerified with a connection from the pool.
+func (p *Pool) PauseSlotVerified(slot int, timeout time.Duration) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.PauseSlotVerified(slot, timeout)
+	})
+}
+
+// PauseSlotVerifiedContext is API.PauseSlotVerifiedContext with a connection from the pool.
+func (p *Pool) PauseSlotVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.PauseSlotVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+// QueueInfo is API.QueueInfo with a connection from the pool.
+func (p *Pool) QueueInfo() (result []SlotQueueInfo, err error) {
+	err = p.Do(context.Background(), func(api *API) error {
+		result, err = api.QueueInfo()
+		return err
+	})
+	return
+}
+
+// QueueInfoContext is API.QueueInfoContext with a connection from the pool.
+func (p *Pool) QueueInfoContext(ctx context.Context) (result []SlotQueueInfo, err error) {
+	err = p.Do(ctx, func(api *API) error {
+		result, err = api.QueueInfoContext(ctx)
+		return err
+	})
+	return
+}
+
+// Reconcile is API.Reconcile with a connection from the pool.
+func (p *Pool) Reconcile(config *Config, dryRun bool) (result *Diff, err error) {
+	err = p.Do(context.Background(), func(api *API) error {
+		result, err = api.Reconcile(config, dryRun)
+		return err
+	})
+	return
+}
+
+// ReconcileContext is API.ReconcileContext with a connection from the pool.
+func (p *Pool) ReconcileContext(
+	ctx context.Context,
+	config *Config,
+	dryRun bool,
+) (result *Diff, err error) {
+	err = p.Do(ctx, func(api *API) error {
+		result, err = api.ReconcileContext(ctx, config, dryRun)
+		return err
+	})
+	return
+}
+
+// RequestID is API.RequestID with a connection from the pool.
+func (p *Pool) RequestID() error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.RequestID()
+	})
+}
+
+// RequestIDContext is API.RequestIDContext with a connection from the pool.
+func (p *Pool) RequestIDContext(ctx context.Context) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.RequestIDContext(ctx)
+	})
+}
+
+// RequestWS is API.RequestWS with a connection from the pool.
+func (p *Pool) RequestWS() error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.RequestWS()
+	})
+}
+
+// RequestWSContext is API.RequestWSContext with a connection from the pool.
+func (p *Pool) RequestWSContext(ctx context.Context) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.RequestWSContext(ctx)
+	})
+}
+
+// Save is API.Save with a connection from the pool.
+func (p *Pool) Save(path string) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.Save(path)
+	})
+}
+
+// SaveContext is API.SaveContext with a connection from the pool.
+func (p *Pool) SaveContext(ctx context.Context, path string) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.SaveContext(ctx, path)
+	})
+}
+
+// Screensaver is API.Screensaver with a connection from the pool.
+func (p *Pool) Screensaver() error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.Screensaver()
+	})
+}
+
+// ScreensaverContext is API.ScreensaverContext with a connection from the pool.
+func (p *Pool) ScreensaverContext(ctx context.Context) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.ScreensaverContext(ctx)
+	})
+}
+
+// Shutdown is API.Shutdown with a connection from the pool.
+func (p *Pool) Shutdown() error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.Shutdown()
+	})
+}
+
+// ShutdownContext is API.ShutdownContext with a connection from the pool.
+func (p *Pool) ShutdownContext(ctx context.Context) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.ShutdownContext(ctx)
+	})
+}
+
+// SimulationInfo is API.SimulationInfo with a connection from the pool.
+func (p *Pool) SimulationInfo(slot int, dst *SimulationInfo) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.SimulationInfo(slot, dst)
+	})
+}
+
+// SimulationInfoContext is API.SimulationInfoContext with a connection from the pool.
+func (p *Pool) SimulationInfoContext(ctx context.Context, slot int, dst *SimulationInfo) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.SimulationInfoContext(ctx, slot, dst)
+	})
+}
+
+// SlotAdd is API.SlotAdd with a connection from the pool.
+func (p *Pool) SlotAdd(slotType SlotType, changes SlotChanges) (result int, err error) {
+	err = p.Do(context.Background(), func(api *API) error {
+		result, err = api.SlotAdd(slotType, changes)
+		return err
+	})
+	return
+}
+
+// SlotAddContext is API.SlotAddContext with a connection from the pool.
+func (p *Pool) SlotAddContext(
+	ctx context.Context,
+	slotType SlotType,
+	changes SlotChanges,
+) (result int, err error) {
+	err = p.Do(ctx, func(api *API) error {
+		result, err = api.SlotAddContext(ctx, slotType, changes)
+		return err
+	})
+	return
+}
+
+// SlotDelete is API.SlotDelete with a connection from the pool.
+func (p *Pool) SlotDelete(slot int) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.SlotDelete(slot)
+	})
+}
+
+// SlotDeleteContext is API.SlotDeleteContext with a connection from the pool.
+func (p *Pool) SlotDeleteContext(ctx context.Context, slot int) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.SlotDeleteContext(ctx, slot)
+	})
+}
+
+// SlotDeleteVerified is API.SlotDeleteVerified with a connection from the pool.
+func (p *Pool) SlotDeleteVerified(slot int, timeout time.Duration) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.SlotDeleteVerified(slot, timeout)
+	})
+}
+
+// SlotDeleteVerifiedContext is API.SlotDeleteVerifiedContext with a connection from the pool.
+func (p *Pool) SlotDeleteVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.SlotDeleteVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+// SlotInfo is API.SlotInfo with a connection from the pool.
+func (p *Pool) SlotInfo() (result []SlotInfo, err error) {
+	err = p.Do(context.Background(), func(api *API) error {
+		result, err = api.SlotInfo()
+		return err
+	})
+	return
+}
+
+// SlotInfoContext is API.SlotInfoContext with a connection from the pool.
+func (p *Pool) SlotInfoContext(ctx context.Context) (result []SlotInfo, err error) {
+	err = p.Do(ctx, func(api *API) error {
+		result, err = api.SlotInfoContext(ctx)
+		return err
+	})
+	return
+}
+
+// SlotModify is API.SlotModify with a connection from the pool.
+func (p *Pool) SlotModify(slot int, slotType SlotType, changes SlotChanges) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.SlotModify(slot, slotType, changes)
+	})
+}
+
+// SlotModifyContext is API.SlotModifyContext with a connection from the pool.
+func (p *Pool) SlotModifyContext(
+	ctx context.Context,
+	slot int,
+	slotType SlotType,
+	changes SlotChanges,
+) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.SlotModifyContext(ctx, slot, slotType, changes)
+	})
+}
+
+// SlotOptionsGet is API.SlotOptionsGet with a connection from the pool.
+func (p *Pool) SlotOptionsGet(slot int, dst *SlotOptions) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.SlotOptionsGet(slot, dst)
+	})
+}
+
+// SlotOptionsGetContext is API.SlotOptionsGetContext with a connection from the pool.
+func (p *Pool) SlotOptionsGetContext(ctx context.Context, slot int, dst *SlotOptions) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.SlotOptionsGetContext(ctx, slot, dst)
+	})
+}
+
+// SlotOptionsReset is API.SlotOptionsReset with a connection from the pool.
+func (p *Pool) SlotOptionsReset(slot int, keys ...string) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.SlotOptionsReset(slot, keys...)
+	})
+}
+
+// SlotOptionsResetContext is API.SlotOptionsResetContext with a connection from the pool.
+func (p *Pool) SlotOptionsResetContext(ctx context.Context, slot int, keys ...string) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.SlotOptionsResetContext(ctx, slot, keys...)
+	})
+}
+
+// SlotOptionsSet is API.SlotOptionsSet with a connection from the pool.
+func (p *Pool) SlotOptionsSet(slot int, key string, value interface{}) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.SlotOptionsSet(slot, key, value)
+	})
+}
+
+// SlotOptionsSetContext is API.SlotOptionsSetContext with a connection from the pool.
+func (p *Pool) SlotOptionsSetContext(
+	ctx context.Context,
+	slot int,
+	key string,
+	value interface{},
+) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.SlotOptionsSetContext(ctx, slot, key, value)
+	})
+}
+
+// SlotOptionsUpdate is API.SlotOptionsUpdate with a connection from the pool.
+func (p *Pool) SlotOptionsUpdate(slot int, changes SlotChanges) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.SlotOptionsUpdate(slot, changes)
+	})
+}
+
+// SlotOptionsUpdateContext is API.SlotOptionsUpdateContext with a connection from the pool.
+func (p *Pool) SlotOptionsUpdateContext(ctx context.Context, slot int, changes SlotChanges) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.SlotOptionsUpdateContext(ctx, slot, changes)
+	})
+}
+
+// SubscribeLog is API.SubscribeLog with a connection from the pool, which is lent until ctx is
+// done.
+func (p *Pool) SubscribeLog(ctx context.Context) (result <-chan LogLine, err error) {
+	err = p.lend(ctx, func(api *API) error {
+		result, err = api.SubscribeLog(ctx)
+		return err
+	})
+	return
+}
+
+// UnpauseAll is API.UnpauseAll with a connection from the pool.
+func (p *Pool) UnpauseAll() error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.UnpauseAll()
+	})
+}
+
+// UnpauseAllContext is API.UnpauseAllContext with a connection from the pool.
+func (p *Pool) UnpauseAllContext(ctx context.Context) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.UnpauseAllContext(ctx)
+	})
+}
+
+// UnpauseSlot is API.UnpauseSlot with a connection from the pool.
+func (p *Pool) UnpauseSlot(slot int) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.UnpauseSlot(slot)
+	})
+}
+
+// UnpauseSlotContext is API.UnpauseSlotContext with a connection from the pool.
+func (p *Pool) UnpauseSlotContext(ctx context.Context, slot int) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.UnpauseSlotContext(ctx, slot)
+	})
+}
+
+// UnpauseSlotVerified is API.UnpauseSlotVerified with a connection from the pool.
+func (p *Pool) UnpauseSlotVerified(slot int, timeout time.Duration) error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.UnpauseSlotVerified(slot, timeout)
+	})
+}
+
+// UnpauseSlotVerifiedContext is API.UnpauseSlotVerifiedContext with a connection from the pool.
+func (p *Pool) UnpauseSlotVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.UnpauseSlotVerifiedContext(ctx, slot, timeout)
+	})
+}
+
+// UpdatesAdd returns an error because API.UpdatesAdd changes the state of a connection. Use Do
+// instead.
+func (p *Pool) UpdatesAdd(id int, rate time.Duration, expression string) error {
+	return connectionStateError("UpdatesAdd")
+}
+
+// UpdatesAddContext returns an error because API.UpdatesAddContext changes the state of a
+// connection. Use Do instead.
+func (p *Pool) UpdatesAddContext(
+	ctx context.Context,
+	id int,
+	rate time.Duration,
+	expression string,
+) error {
+	return connectionStateError("UpdatesAddContext")
+}
+
+// UpdatesClear returns an error because API.UpdatesClear changes the state of a connection. Use Do
+// instead.
+func (p *Pool) UpdatesClear() error {
+	return connectionStateError("UpdatesClear")
+}
+
+// UpdatesClearContext returns an error because API.UpdatesClearContext changes the state of a
+// connection. Use Do instead.
+func (p *Pool) UpdatesClearContext(ctx context.Context) error {
+	return connectionStateError("UpdatesClearContext")
+}
+
+// UpdatesDel returns an error because API.UpdatesDel changes the state of a connection. Use Do
+// instead.
+func (p *Pool) UpdatesDel(id int) error {
+	return connectionStateError("UpdatesDel")
+}
+
+// UpdatesDelContext returns an error because API.UpdatesDelContext changes the state of a
+// connection. Use Do instead.
+func (p *Pool) UpdatesDelContext(ctx context.Context, id int) error {
+	return connectionStateError("UpdatesDelContext")
+}
+
+// UpdatesList is API.UpdatesList with a connection from the pool.
+func (p *Pool) UpdatesList() (result string, err error) {
+	err = p.Do(context.Background(), func(api *API) error {
+		result, err = api.UpdatesList()
+		return err
+	})
+	return
+}
+
+// UpdatesListContext is API.UpdatesListContext with a connection from the pool.
+func (p *Pool) UpdatesListContext(ctx context.Context) (result string, err error) {
+	err = p.Do(ctx, func(api *API) error {
+		result, err = api.UpdatesListContext(ctx)
+		return err
+	})
+	return
+}
+
+// UpdatesReset is API.UpdatesReset with a connection from the pool.
+func (p *Pool) UpdatesReset() error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.UpdatesReset()
+	})
+}
+
+// UpdatesResetContext is API.UpdatesResetContext with a connection from the pool.
+func (p *Pool) UpdatesResetContext(ctx context.Context) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.UpdatesResetContext(ctx)
+	})
+}
+
+// Uptime is API.Uptime with a connection from the pool.
+func (p *Pool) Uptime() (result FAHDuration, err error) {
+	err = p.Do(context.Background(), func(api *API) error {
+		result, err = api.Uptime()
+		return err
+	})
+	return
+}
+
+// UptimeContext is API.UptimeContext with a connection from the pool.
+func (p *Pool) UptimeContext(ctx context.Context) (result FAHDuration, err error) {
+	err = p.Do(ctx, func(api *API) error {
+		result, err = api.UptimeContext(ctx)
+		return err
+	})
+	return
+}
+
+// WaitForUnits is API.WaitForUnits with a connection from the pool.
+func (p *Pool) WaitForUnits() error {
+	return p.Do(context.Background(), func(api *API) error {
+		return api.WaitForUnits()
+	})
+}
+
+// WaitForUnitsContext is API.WaitForUnitsContext with a connection from the pool.
+func (p *Pool) WaitForUnitsContext(ctx context.Context) error {
+	return p.Do(ctx, func(api *API) error {
+		return api.WaitForUnitsContext(ctx)
+	})
+}
+
+// WatchEvents is API.WatchEvents with a connection from the pool, which is lent until ctx is done.
+func (p *Pool) WatchEvents(
+	ctx context.Context,
+	rate time.Duration,
+) (result <-chan WatchEvent, err error) {
+	err = p.lend(ctx, func(api *API) error {
+		result, err = api.WatchEvents(ctx, rate)
+		return err
+	})
+	return
+}
+
+// WatchOptions is API.WatchOptions with a connection from the pool, which is lent until ctx is
+// done.
+func (p *Pool) WatchOptions(
+	ctx context.Context,
+	rate time.Duration,
+) (result <-chan Options, err error) {
+	err = p.lend(ctx, func(api *API) error {
+		result, err = api.WatchOptions(ctx, rate)
+		return err
+	})
+	return
+}
+
+// WatchQueueInfo is API.WatchQueueInfo with a connection from the pool, which is lent until ctx is
+// done.
+func (p *Pool) WatchQueueInfo(
+	ctx context.Context,
+	rate time.Duration,
+) (result <-chan []SlotQueueInfo, err error) {
+	err = p.lend(ctx, func(api *API) error {
+		result, err = api.WatchQueueInfo(ctx, rate)
+		return err
+	})
+	return
+}
+
+// WatchSlotInfo is API.WatchSlotInfo with a connection from the pool, which is lent until ctx is
+// done.
+func (p *Pool) WatchSlotInfo(
+	ctx context.Context,
+	rate time.Duration,
+) (result <-chan []SlotInfo, err error) {
+	err = p.lend(ctx, func(api *API) error {
+		result, err = api.WatchSlotInfo(ctx, rate)
+		return err
+	})
+	return
+}
@@ -0,0 +1,628 @@
+package fahapi
+
+import (
+	"bytes"
+	"fmt"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// PyON is the Python Object Notation format that FAH uses for structured responses. A message looks
+// like this:
+//  PyON 1 units
+//  [{"id": "00", "state": "RUNNING", "error": None}]
+//  ---
+// The body is a Python literal made of dicts, lists, tuples, strings, numbers, None, True and
+// False. It is decoded by translating it to JSON, so dst can use json tags and UnmarshalJSON().
+// https://pypi.org/project/pon/
+
+// PyONSyntaxError describes a malformed PyON message.
+type PyONSyntaxError struct {
+	Msg    string
+	Offset int // Byte offset from the start of the message
+	Line   int // 1-based
+	Column int // 1-based, in bytes
+}
+
+func (e *PyONSyntaxError) Error() string {
+	return fmt.Sprintf("PyON syntax error at line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// UnmarshalPyON decodes a PyON message into dst. None is decoded as JSON null, which sets
+// interfaces, pointers, maps and slices to nil.
+func UnmarshalPyON(b []byte, dst interface{}) error {
+	_, body, offset, err := splitPyONMessage(b)
+	if err != nil {
+		return err
+	}
+
+	converted, err := pyonToJSON(b, offset, body)
+	if err != nil {
+		return err
+	}
+
+	return errors.WithStack(json.Unmarshal(converted, dst))
+}
+
+// splitPyONMessage returns the message name, body and the offset of the body in b.
+func splitPyONMessage(b []byte) (string, []byte, int, error) {
+	const prefix = "PyON"
+	const suffix = "\n---"
+
+	if !bytes.HasPrefix(b, []byte(prefix)) || !bytes.HasSuffix(b, []byte(suffix)) {
+		return "", nil, 0, errors.Errorf("invalid PyON format: %s", b)
+	}
+
+	headerEnd := bytes.IndexByte(b, '\n')
+	start := headerEnd + 1
+	end := len(b) - len(suffix)
+	if start > end {
+		start = end
+	}
+
+	// The header is "PyON <version> <name>"; the name is the last field.
+	var name string
+	if fields := bytes.Fields(b[len(prefix):headerEnd]); len(fields) > 0 {
+		name = string(fields[len(fields)-1])
+	}
+
+	return name, b[start:end], start, nil
+}
+
+// ParsePyONString decodes a quoted PyON string.
+func ParsePyONString(b []byte) (string, error) {
+	d := &pyonDecoder{src: b, data: b}
+	if len(b) == 0 || (b[0] != '"' && b[0] != '\'') {
+		return "", d.syntaxError("b is not a valid PyON string")
+	}
+
+	s, err := d.readString()
+	if err != nil {
+		return "", err
+	}
+
+	if d.pos != len(b) {
+		return "", d.syntaxError("unexpected data after string")
+	}
+	return s, nil
+}
+
+// pyonToJSON converts the PyON value in body to JSON. src is the whole message and offset is the
+// position of body within src; they are only used for error positions.
+func pyonToJSON(src []byte, offset int, body []byte) ([]byte, error) {
+	d := &pyonDecoder{
+		src:    src,
+		offset: offset,
+		data:   body,
+		out:    bytes.NewBuffer(make([]byte, 0, len(body))),
+	}
+
+	d.skipSpace()
+	if d.pos == len(d.data) {
+		return nil, d.syntaxError("empty message")
+	}
+
+	if err := d.value(); err != nil {
+		return nil, err
+	}
+
+	d.skipSpace()
+	if d.pos != len(d.data) {
+		return nil, d.syntaxError("unexpected data after value")
+	}
+
+	return d.out.Bytes(), nil
+}
+
+type pyonDecoder struct {
+	src    []byte
+	offset int
+	data   []byte
+	pos    int
+	out    *bytes.Buffer
+}
+
+func (d *pyonDecoder) syntaxError(msg string) error {
+	offset := d.offset + d.pos
+	if offset > len(d.src) {
+		offset = len(d.src)
+	}
+
+	line := bytes.Count(d.src[:offset], []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(d.src[:offset], '\n')
+	return errors.WithStack(&PyONSyntaxError{Msg: msg, Offset: offset, Line: line, Column: column})
+}
+
+func (d *pyonDecoder) skipSpace() {
+	for d.pos < len(d.data) {
+		switch d.data[d.pos] {
+		case ' ', '\t', '\n', '\r':
+			d.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (d *pyonDecoder) value() error {
+	if d.pos >= len(d.data) {
+		return d.syntaxError("unexpected end of message")
+	}
+
+	c := d.data[d.pos]
+	switch {
+	case c == '{':
+		return d.dict()
+	case c == '[':
+		return d.sequence(']')
+	case c == '(':
+		return d.sequence(')')
+	case c == '"' || c == '\'':
+		s, err := d.readString()
+		if err != nil {
+			return err
+		}
+		writeJSONString(d.out, s)
+		return nil
+	case c == '-' || c == '+' || c == '.' || ('0' <= c && c <= '9'):
+		return d.number()
+	case isIdentifierByte(c):
+		return d.constant()
+	}
+
+	return d.syntaxError(fmt.Sprintf("unexpected character %q", c))
+}
+
+func (d *pyonDecoder) dict() error {
+	d.pos++ // {
+	d.out.WriteByte('{')
+
+	first := true
+	for {
+		d.skipSpace()
+		if d.pos >= len(d.data) {
+			return d.syntaxError("unterminated dict")
+		}
+
+		if d.data[d.pos] == '}' {
+			d.pos++
+			d.out.WriteByte('}')
+			return nil
+		}
+
+		if !first {
+			d.out.WriteByte(',')
+		}
+		first = false
+
+		if c := d.data[d.pos]; c != '"' && c != '\'' {
+			return d.syntaxError("dict key must be a string")
+		}
+
+		key, err := d.readString()
+		if err != nil {
+			return err
+		}
+		writeJSONString(d.out, key)
+
+		d.skipSpace()
+		if d.pos >= len(d.data) || d.data[d.pos] != ':' {
+			return d.syntaxError("expected ':' after dict key")
+		}
+		d.pos++
+		d.out.WriteByte(':')
+
+		d.skipSpace()
+		if err := d.value(); err != nil {
+			return err
+		}
+
+		if err := d.separator('}'); err != nil {
+			return err
+		}
+	}
+}
+
+// sequence reads a list or tuple, which are both decoded as JSON arrays.
+func (d *pyonDecoder) sequence(end byte) error {
+	d.pos++ // [ or (
+	d.out.WriteByte('[')
+
+	first := true
+	for {
+		d.skipSpace()
+		if d.pos >= len(d.data) {
+			return d.syntaxError("unterminated sequence")
+		}
+
+		if d.data[d.pos] == end {
+			d.pos++
+			d.out.WriteByte(']')
+			return nil
+		}
+
+		if !first {
+			d.out.WriteByte(',')
+		}
+		first = false
+
+		if err := d.value(); err != nil {
+			return err
+		}
+
+		if err := d.separator(end); err != nil {
+			return err
+		}
+	}
+}
+
+// separator consumes the comma after an item. A trailing comma before end is allowed.
+func (d *pyonDecoder) separator(end byte) error {
+	d.skipSpace()
+	if d.pos >= len(d.data) {
+		return d.syntaxError("unexpected end of message")
+	}
+
+	switch d.data[d.pos] {
+	case ',':
+		d.pos++
+		return nil
+	case end:
+		return nil
+	}
+
+	return d.syntaxError(fmt.Sprintf("expected ',' or %q", end))
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+func (d *pyonDecoder) constant() error {
+	start := d.pos
+	for d.pos < len(d.data) && isIdentifierByte(d.data[d.pos]) {
+		d.pos++
+	}
+
+	name := string(d.data[start:d.pos])
+	switch name {
+	case "None":
+		d.out.WriteString("null")
+	case "True":
+		d.out.WriteString("true")
+	case "False":
+		d.out.WriteString("false")
+	default:
+		d.pos = start
+		return d.syntaxError(fmt.Sprintf("unknown name %q", name))
+	}
+	return nil
+}
+
+func (d *pyonDecoder) number() error {
+	start := d.pos
+	if c := d.data[d.pos]; c == '-' || c == '+' {
+		d.pos++
+	}
+
+	digitsStart := d.pos
+	for d.pos < len(d.data) && isNumberByte(d.data[d.pos]) {
+		d.pos++
+	}
+
+	text := string(d.data[digitsStart:d.pos])
+	if _, err := strconv.ParseFloat(text, 64); err != nil || text == "" {
+		d.pos = start
+		return d.syntaxError("invalid number")
+	}
+
+	if d.data[start] == '-' {
+		d.out.WriteByte('-')
+	}
+	d.out.WriteString(jsonNumber(text))
+	return nil
+}
+
+func isNumberByte(c byte) bool {
+	return ('0' <= c && c <= '9') || c == '.' || c == 'e' || c == 'E' || c == '+' || c == '-'
+}
+
+// jsonNumber rewrites a Python number so that it is a valid JSON number, which cannot have leading
+// zeros or start or end with a decimal point.
+func jsonNumber(s string) string {
+	mantissa, exponent := s, ""
+	if i := bytes.IndexAny([]byte(s), "eE"); i >= 0 {
+		mantissa, exponent = s[:i], s[i:]
+	}
+
+	for len(mantissa) > 1 && mantissa[0] == '0' && mantissa[1] != '.' {
+		mantissa = mantissa[1:]
+	}
+
+	if mantissa[0] == '.' {
+		mantissa = "0" + mantissa
+	}
+
+	if mantissa[len(mantissa)-1] == '.' {
+		mantissa += "0"
+	}
+
+	return mantissa + exponent
+}
+
+// readString reads a quoted string starting at d.pos and returns its value.
+func (d *pyonDecoder) readString() (string, error) {
+	quote := d.data[d.pos]
+	start := d.pos
+	d.pos++
+
+	var result []byte
+	for {
+		if d.pos >= len(d.data) {
+			d.pos = start
+			return "", d.syntaxError("unterminated string")
+		}
+
+		c := d.data[d.pos]
+		if c == quote {
+			d.pos++
+			return string(result), nil
+		}
+
+		if c == '\n' {
+			return "", d.syntaxError("newline in string")
+		}
+
+		if c != '\\' {
+			result = append(result, c)
+			d.pos++
+			continue
+		}
+
+		if d.pos+1 >= len(d.data) {
+			return "", d.syntaxError("unterminated escape sequence")
+		}
+
+		escape := d.data[d.pos+1]
+		switch escape {
+		case 'n':
+			result = append(result, '\n')
+		case 'r':
+			result = append(result, '\r')
+		case 't':
+			result = append(result, '\t')
+		case 'b':
+			result = append(result, '\b')
+		case 'f':
+			result = append(result, '\f')
+		case 'v':
+			result = append(result, '\v')
+		case 'a':
+			result = append(result, '\a')
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			length := 1
+			for length < 3 && d.pos+1+length < len(d.data) &&
+				'0' <= d.data[d.pos+1+length] && d.data[d.pos+1+length] <= '7' {
+				length++
+			}
+
+			n, _ := strconv.ParseUint(string(d.data[d.pos+1:d.pos+1+length]), 8, 32)
+			result = append(result, string(rune(n))...)
+			d.pos += length - 1
+		case '\\', '"', '\'':
+			result = append(result, escape)
+		case 'x', 'u', 'U':
+			length := 2
+			if escape == 'u' {
+				length = 4
+			} else if escape == 'U' {
+				length = 8
+			}
+			if d.pos+2+length > len(d.data) {
+				return "", d.syntaxError("unterminated escape sequence")
+			}
+
+			n, err := strconv.ParseUint(string(d.data[d.pos+2:d.pos+2+length]), 16, 32)
+			if err != nil || !utf8.ValidRune(rune(n)) {
+				return "", d.syntaxError("invalid escape sequence")
+			}
+
+			result = append(result, string(rune(n))...)
+			d.pos += length
+		default:
+			// Python keeps unknown escape sequences as they are
+			result = append(result, '\\', escape)
+		}
+		d.pos += 2
+	}
+}
+
+func writeJSONString(buffer *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+
+	buffer.WriteByte('"')
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			buffer.WriteString(`\ufffd`)
+		case r == '"' || r == '\\':
+			buffer.WriteByte('\\')
+			buffer.WriteByte(byte(r))
+		case r < 0x20:
+			buffer.WriteString(`\u00`)
+			buffer.WriteByte(hex[r>>4])
+			buffer.WriteByte(hex[r&0xf])
+		default:
+			buffer.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	buffer.WriteByte('"')
+}
+
+// pyonJSON encodes the values of MarshalPyON. Map keys are sorted so that messages are the same
+// every time.
+var pyonJSON = jsoniter.Config{SortMapKeys: true}.Froze()
+
+// MarshalPyON encodes v as a PyON message with the given name. v is first encoded with json tags
+// and MarshalJSON(), then written in the same layout that FAHClient uses, so UnmarshalPyON() and
+// MarshalPyON() round-trip the client's messages.
+func MarshalPyON(name string, v interface{}) ([]byte, error) {
+	if strings.ContainsAny(name, " \n") {
+		return nil, errors.Errorf("invalid PyON message name: %s", name)
+	}
+
+	b, err := pyonJSON.Marshal(v)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	iter := jsoniter.ParseBytes(pyonJSON, b)
+	root := readJSONNode(iter)
+	// jsoniter reports EOF after a number at the end of the input
+	if iter.Error != nil && iter.Error != io.EOF {
+		return nil, errors.WithStack(iter.Error)
+	}
+
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("PyON 1 ")
+	buffer.WriteString(name)
+	buffer.WriteByte('\n')
+	root.writePyON(buffer, 0)
+	buffer.WriteString("\n---")
+	return buffer.Bytes(), nil
+}
+
+// jsonNode is a decoded JSON value that keeps the order of dict keys.
+type jsonNode struct {
+	valueType jsoniter.ValueType
+	scalar    string // PyON representation of a string, number, bool or null
+	keys      []string
+	items     []*jsonNode
+}
+
+func readJSONNode(iter *jsoniter.Iterator) *jsonNode {
+	node := &jsonNode{valueType: iter.WhatIsNext()}
+	switch node.valueType {
+	case jsoniter.ObjectValue:
+		iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+			node.keys = append(node.keys, key)
+			node.items = append(node.items, readJSONNode(iter))
+			return true
+		})
+	case jsoniter.ArrayValue:
+		for iter.ReadArray() {
+			node.items = append(node.items, readJSONNode(iter))
+		}
+	case jsoniter.StringValue:
+		node.scalar = quotePyONString(iter.ReadString())
+	case jsoniter.NumberValue:
+		node.scalar = string(iter.ReadNumber())
+	case jsoniter.BoolValue:
+		if iter.ReadBool() {
+			node.scalar = "True"
+		} else {
+			node.scalar = "False"
+		}
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		node.scalar = "None"
+	default:
+		iter.ReportError("readJSONNode", "invalid value")
+	}
+	return node
+}
+
+func (n *jsonNode) isContainer() bool {
+	return n.valueType == jsoniter.ObjectValue || n.valueType == jsoniter.ArrayValue
+}
+
+// inline returns true if the node is written on one line. The client writes containers of scalars
+// on one line when they are nested at least two levels deep, like the options in slot-info.
+func (n *jsonNode) inline(depth int) bool {
+	if len(n.items) == 0 {
+		return true
+	}
+
+	if depth < 2 {
+		return false
+	}
+
+	for _, item := range n.items {
+		if item.isContainer() {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *jsonNode) writePyON(buffer *bytes.Buffer, depth int) {
+	if !n.isContainer() {
+		buffer.WriteString(n.scalar)
+		return
+	}
+
+	begin, end := byte('['), byte(']')
+	if n.valueType == jsoniter.ObjectValue {
+		begin, end = '{', '}'
+	}
+
+	inline := n.inline(depth)
+	buffer.WriteByte(begin)
+	for i, item := range n.items {
+		if i > 0 {
+			buffer.WriteByte(',')
+			if inline {
+				buffer.WriteByte(' ')
+			}
+		}
+
+		if !inline {
+			buffer.WriteByte('\n')
+			buffer.WriteString(strings.Repeat("  ", depth+1))
+		}
+
+		if n.valueType == jsoniter.ObjectValue {
+			buffer.WriteString(quotePyONString(n.keys[i]))
+			buffer.WriteString(": ")
+		}
+		item.writePyON(buffer, depth+1)
+	}
+
+	if !inline {
+		buffer.WriteByte('\n')
+		buffer.WriteString(strings.Repeat("  ", depth))
+	}
+	buffer.WriteByte(end)
+}
+
+func quotePyONString(s string) string {
+	const hex = "0123456789abcdef"
+
+	b := make([]byte, 0, len(s)+2)
+	b = append(b, '"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			b = append(b, '\\', c)
+		case '\n':
+			b = append(b, '\\', 'n')
+		case '\r':
+			b = append(b, '\\', 'r')
+		case '\t':
+			b = append(b, '\\', 't')
+		default:
+			if c < 0x20 || c == 0x7f {
+				b = append(b, '\\', 'x', hex[c>>4], hex[c&0xf])
+			} else {
+				b = append(b, c)
+			}
+		}
+	}
+	return string(append(b, '"'))
+}
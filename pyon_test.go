@@ -0,0 +1,359 @@
+package fahapi
+
+import (
+	"github.com/MakotoE/checkerror"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+func TestParsePyONString(t *testing.T) {
+	tests := []struct {
+		s           string
+		expected    string
+		expectError bool
+	}{
+		{
+			``,
+			"",
+			true,
+		},
+		{
+			`""`,
+			"",
+			false,
+		},
+		{
+			`"\n\"\\\x01"`,
+			"\n\"\\\x01",
+			false,
+		},
+		{
+			`"a\x01a"`,
+			"a\x01a",
+			false,
+		},
+		{
+			`'a"b\''`,
+			`a"b'`,
+			false,
+		},
+		{
+			`"\xe9é\q"`,
+			`éé\q`,
+			false,
+		},
+		{
+			`"a`,
+			"",
+			true,
+		},
+		{
+			`"a"b`,
+			"",
+			true,
+		},
+		{
+			`"\x0"`,
+			"",
+			true,
+		},
+	}
+
+	for i, test := range tests {
+		result, err := ParsePyONString([]byte(test.s))
+		assert.Equal(t, test.expected, result, i)
+		checkerror.Check(t, test.expectError, err, i)
+	}
+}
+
+func BenchmarkParsePyONString(b *testing.B) {
+	// BenchmarkParsePyONString-8   	13281836	        90.2 ns/op
+	var result string
+	for i := 0; i < b.N; i++ {
+		result, _ = ParsePyONString([]byte(`"a\x01\n"`))
+	}
+	_ = result
+}
+
+func TestUnmarshalPyON(t *testing.T) {
+	tests := []struct {
+		s           string
+		expected    interface{}
+		expectError bool
+	}{
+		{
+			"",
+			nil,
+			true,
+		},
+		{
+			"PyON\n---",
+			nil,
+			true,
+		},
+		{
+			"PyON\n\n---",
+			nil,
+			true,
+		},
+		{
+			"PyON\n1\n---",
+			1.0,
+			false,
+		},
+		{
+			"PyON\nTrue\n---",
+			true,
+			false,
+		},
+		{
+			"PyON\n{\"\": None}\n---",
+			map[string]interface{}{"": nil},
+			false,
+		},
+		{
+			"PyON 1 a\n[None, True, (False, -1.5e1), \"None\", {'a': (),},]\n---",
+			[]interface{}{
+				nil,
+				true,
+				[]interface{}{false, -15.0},
+				"None",
+				map[string]interface{}{"a": []interface{}{}},
+			},
+			false,
+		},
+		{
+			"PyON 1 a\n\"a: True\\x00\"\n---",
+			"a: True\x00",
+			false,
+		},
+		{
+			"PyON 1 a\n[.5, 007, 1.]\n---",
+			[]interface{}{0.5, 7.0, 1.0},
+			false,
+		},
+		{
+			"PyON 1 a\n[1 2]\n---",
+			nil,
+			true,
+		},
+		{
+			"PyON 1 a\n{1: 2}\n---",
+			nil,
+			true,
+		},
+		{
+			"PyON 1 a\nnull\n---",
+			nil,
+			true,
+		},
+		{
+			"PyON 1 a\n[1, 2\n---",
+			nil,
+			true,
+		},
+		{
+			"PyON 1 a\n1 1\n---",
+			nil,
+			true,
+		},
+	}
+
+	for i, test := range tests {
+		var dst interface{}
+		checkerror.Check(t, test.expectError, UnmarshalPyON([]byte(test.s), &dst), i)
+		assert.Equal(t, test.expected, dst, i)
+	}
+}
+
+func TestUnmarshalPyON_struct(t *testing.T) {
+	s := "PyON 1 units\n" +
+		`[{"id": "01", "state": None, "project": 1234, "ppd": "100", "eta": "1 hours 2 mins",` +
+		` "deadline": "<invalid>", "error": "NO_ERROR"}]` +
+		"\n---"
+
+	var result []SlotQueueInfo
+	require.Nil(t, UnmarshalPyON([]byte(s), &result))
+	require.Len(t, result, 1)
+	assert.Equal(t, "01", result[0].ID)
+	assert.Equal(t, "", result[0].State)
+	assert.Equal(t, 1234, result[0].Project)
+	assert.Equal(t, StringInt(100), result[0].PPD)
+	assert.Equal(t, FAHDuration(3720000000000), result[0].ETA)
+	assert.True(t, result[0].Deadline.Invalid())
+
+	{
+		var options SlotOptions
+		assert.Nil(t, UnmarshalPyON([]byte("PyON 1 slot-options\n{'paused': None}\n---"), &options))
+		assert.Equal(t, StringBool(false), options.Paused)
+	}
+}
+
+func TestUnmarshalPyON_syntaxError(t *testing.T) {
+	var dst interface{}
+	err := UnmarshalPyON([]byte("PyON 1 a\n{\n  'a': [1,\n    x]\n}\n---"), &dst)
+	syntaxError, ok := errors.Cause(err).(*PyONSyntaxError)
+	require.True(t, ok, err)
+	assert.Equal(t, 4, syntaxError.Line)
+	assert.Equal(t, 5, syntaxError.Column)
+	assert.Equal(t, 26, syntaxError.Offset)
+}
+
+func TestSplitPyONMessage(t *testing.T) {
+	name, body, offset, err := splitPyONMessage([]byte("PyON 1 log-update\n\"a\"\n---"))
+	assert.Nil(t, err)
+	assert.Equal(t, "log-update", name)
+	assert.Equal(t, `"a"`, string(body))
+	assert.Equal(t, 18, offset)
+}
+
+func BenchmarkUnparshalPyOn(b *testing.B) {
+	// BenchmarkUnparshalPyOn-8   	 2296100	       527 ns/op
+	var result struct{}
+	for i := 0; i < b.N; i++ {
+		_ = UnmarshalPyON([]byte("PyON\n{\"a\":\"b\"}\n---"), &result)
+	}
+	_ = result
+}
+
+func TestMarshalPyON(t *testing.T) {
+	tests := []struct {
+		name     string
+		v        interface{}
+		expected string
+	}{
+		{
+			"configured",
+			true,
+			"PyON 1 configured\nTrue\n---",
+		},
+		{
+			"a",
+			nil,
+			"PyON 1 a\nNone\n---",
+		},
+		{
+			"ppd",
+			53463.5,
+			"PyON 1 ppd\n53463.5\n---",
+		},
+		{
+			"a",
+			[]interface{}{},
+			"PyON 1 a\n[]\n---",
+		},
+		{
+			"a",
+			map[string]interface{}{"b": "\x01\"\\\n", "a": 1.5},
+			"PyON 1 a\n{\n  \"a\": 1.5,\n  \"b\": \"\\x01\\\"\\\\\\n\"\n}\n---",
+		},
+	}
+
+	for i, test := range tests {
+		result, err := MarshalPyON(test.name, test.v)
+		assert.Nil(t, err, i)
+		assert.Equal(t, test.expected, string(result), i)
+	}
+
+	_, err := MarshalPyON("a b", nil)
+	assert.NotNil(t, err)
+}
+
+// Messages in the format that the client sends
+var pyonRoundTripTests = []struct {
+	message string
+	dst     func() interface{}
+}{
+	{
+		`PyON 1 units
+[
+  {
+    "id": "00",
+    "state": "RUNNING",
+    "error": "NO_ERROR",
+    "project": 16435,
+    "run": 2466,
+    "clone": 2,
+    "gen": 37,
+    "core": "0xa7",
+    "unit": "0x0000002e80fccb0a5e7a7c22d3b8d0e2",
+    "percentdone": "82.59%",
+    "eta": "2 hours 19 mins",
+    "ppd": "53463",
+    "creditestimate": "21356",
+    "waitingon": "",
+    "nextattempt": "0.00 secs",
+    "timeremaining": "8.83 days",
+    "totalframes": 100,
+    "framesdone": 82,
+    "assigned": "2020-06-29T16:02:34Z",
+    "timeout": "2020-06-30T16:02:34Z",
+    "deadline": "<invalid>",
+    "ws": "128.252.203.10",
+    "cs": "0.0.0.0",
+    "attempts": 0,
+    "slot": "00",
+    "tpf": "4 mins 33 secs",
+    "basecredit": "9405"
+  }
+]
+---`,
+		func() interface{} { return &[]SlotQueueInfo{} },
+	},
+	{
+		`PyON 1 slots
+[
+  {
+    "id": "00",
+    "status": "PAUSED",
+    "description": "cpu:3",
+    "options": {"idle": "false", "paused": "true"},
+    "reason": "by user",
+    "idle": False
+  }
+]
+---`,
+		func() interface{} { return &[]SlotInfo{} },
+	},
+	{
+		`PyON 1 info
+[
+  [
+    "FAHClient",
+    ["Version", "7.6.13"],
+    ["Author", "Joseph Coffland <joseph@cauldrondevelopment.com>"]
+  ],
+  [
+    "System",
+    ["CPUs", "4"]
+  ]
+]
+---`,
+		func() interface{} { return &[][]interface{}{} },
+	},
+}
+
+func TestMarshalPyON_roundTrip(t *testing.T) {
+	for i, test := range pyonRoundTripTests {
+		dst := test.dst()
+		require.Nil(t, UnmarshalPyON([]byte(test.message), dst), i)
+
+		name, _, _, _ := splitPyONMessage([]byte(test.message))
+		result, err := MarshalPyON(name, reflect.ValueOf(dst).Elem().Interface())
+		assert.Nil(t, err, i)
+		assert.Equal(t, test.message, string(result), i)
+	}
+}
+
+func TestMarshalPyON_options(t *testing.T) {
+	options := Options{Power: PowerFull, Team: 1, User: "a b", Paused: true}
+	b, err := MarshalPyON("options", options)
+	require.Nil(t, err)
+
+	result := Options{}
+	require.Nil(t, UnmarshalPyON(b, &result))
+	assert.Equal(t, options, result)
+}
@@ -0,0 +1,500 @@
+package fahapi
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Config is the desired state of a client for Reconcile. It can be encoded as JSON to store it, as
+// returned by ExportConfig.
+type Config struct {
+	// Options to set. A nil value resets the option to its default. Options that are not in the
+	// map are not changed.
+	Options OptionChanges `json:"options"`
+	// Slots that should exist. Slots of the client that are not listed are deleted.
+	Slots []SlotConfig `json:"slots"`
+}
+
+// NoSlotID is the ID of a SlotConfig that does not refer to an existing slot. It is the ID of a
+// slot that is decoded from JSON without an id.
+const NoSlotID = -1
+
+// SlotConfig is the desired state of a slot.
+type SlotConfig struct {
+	// ID of an existing slot, or a negative ID such as NoSlotID. If there is no slot with this ID,
+	// a slot of the same type that is not in the config is changed to match, preferring one that
+	// already has the options. Otherwise a slot is added, and the client chooses its ID.
+	ID   int      `json:"id"`
+	Type SlotType `json:"type"`
+	// Nil values reset options, and other options are not changed
+	Options SlotChanges `json:"options"`
+}
+
+// UnmarshalJSON sets ID to NoSlotID if b has no id, so that it does not refer to slot 0.
+func (s *SlotConfig) UnmarshalJSON(b []byte) error {
+	type slotConfig SlotConfig
+	result := slotConfig{ID: NoSlotID}
+	if err := json.Unmarshal(b, &result); err != nil {
+		return errors.WithStack(err)
+	}
+	*s = SlotConfig(result)
+	return nil
+}
+
+// OptionDiff is a change to an option. Desired is nil if the option is reset to its default.
+type OptionDiff struct {
+	Name    string
+	Current string
+	Desired interface{}
+}
+
+func (o OptionDiff) String() string {
+	if o.Desired == nil {
+		return fmt.Sprintf("%s: %q -> default", o.Name, o.Current)
+	}
+	return fmt.Sprintf("%s: %q -> %q", o.Name, o.Current, fmt.Sprintf("%v", o.Desired))
+}
+
+// SlotDiff is a change to an existing slot. Type is empty if the type is not changed.
+type SlotDiff struct {
+	ID      int
+	Type    SlotType
+	Options []OptionDiff
+}
+
+// Diff is the difference between a Config and the state of a client.
+type Diff struct {
+	Options []OptionDiff
+	// Slots to add. After Reconcile has added them, ID is the ID that the client chose.
+	AddSlots    []SlotConfig
+	ModifySlots []SlotDiff
+	DeleteSlots []int
+}
+
+// Empty returns true if there are no changes.
+func (d *Diff) Empty() bool {
+	return len(d.Options) == 0 &&
+		len(d.AddSlots) == 0 &&
+		len(d.ModifySlots) == 0 &&
+		len(d.DeleteSlots) == 0
+}
+
+func (d *Diff) String() string {
+	var b strings.Builder
+	for _, option := range d.Options {
+		fmt.Fprintf(&b, "option %s\n", option)
+	}
+	for _, slot := range d.DeleteSlots {
+		fmt.Fprintf(&b, "delete slot %d\n", slot)
+	}
+	for _, slot := range d.ModifySlots {
+		if slot.Type != "" {
+			fmt.Fprintf(&b, "modify slot %d: type -> %s\n", slot.ID, slot.Type)
+		}
+		for _, option := range slot.Options {
+			fmt.Fprintf(&b, "modify slot %d: %s\n", slot.ID, option)
+		}
+	}
+	for _, slot := range d.AddSlots {
+		args, _ := slot.Options.args()
+		fmt.Fprintf(&b, "add %s slot%s\n", slot.Type, args)
+	}
+	return b.String()
+}
+
+// Diff compares config with the options and slots of the client. It returns an error if config
+// has duplicate slot IDs.
+func (a *API) Diff(config *Config) (*Diff, error) {
+	return a.DiffContext(context.Background(), config)
+}
+
+// DiffContext is Diff with a context.
+func (a *API) DiffContext(ctx context.Context, config *Config) (*Diff, error) {
+	options, err := config.Options.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Diff{}
+
+	if len(options) > 0 {
+		all, err := a.optionsList(ctx, "options -a")
+		if err != nil {
+			return nil, err
+		}
+
+		changed, err := a.OptionsChangedContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Options = diffOptions(OptionChanges(options), all, changed)
+	}
+
+	slots, err := a.SlotInfoContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[int]SlotInfo, len(slots))
+	for _, slot := range slots {
+		id, err := strconv.Atoi(slot.ID)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		current[id] = slot
+	}
+
+	ids := make(map[int]struct{}, len(config.Slots))
+	desired := make(map[int]struct{}, len(config.Slots))
+	var unmatched []SlotConfig
+	for _, slot := range config.Slots {
+		if slot.ID < 0 {
+			unmatched = append(unmatched, slot)
+			continue
+		}
+
+		if _, ok := ids[slot.ID]; ok {
+			return nil, errors.Errorf("duplicate slot id: %d", slot.ID)
+		}
+		ids[slot.ID] = struct{}{}
+
+		info, ok := current[slot.ID]
+		if !ok {
+			unmatched = append(unmatched, slot)
+			continue
+		}
+		desired[slot.ID] = struct{}{}
+
+		slotDiff, err := a.diffSlot(ctx, slot, info)
+		if err != nil {
+			return nil, err
+		}
+
+		if slotDiff.Type != "" || len(slotDiff.Options) > 0 {
+			result.ModifySlots = append(result.ModifySlots, slotDiff)
+		}
+	}
+
+	// The ID of a slot that was added by Reconcile is not in config, so unmatched slots take the
+	// place of other slots before any slot is added
+	for _, exact := range []bool{true, false} {
+		unmatched, err = a.matchSlots(ctx, result, unmatched, current, desired, exact)
+		if err != nil {
+			return nil, err
+		}
+	}
+	result.AddSlots = unmatched
+
+	for id := range current {
+		if _, ok := desired[id]; !ok {
+			result.DeleteSlots = append(result.DeleteSlots, id)
+		}
+	}
+	sort.Ints(result.DeleteSlots)
+
+	return result, nil
+}
+
+// matchSlots matches each slot in unmatched with a slot of the client that is not desired and has
+// the same type. If exact is true, the slot must also have the desired options. Matched slots are
+// added to desired and result, and the slots that are still unmatched are returned.
+func (a *API) matchSlots(
+	ctx context.Context,
+	result *Diff,
+	unmatched []SlotConfig,
+	current map[int]SlotInfo,
+	desired map[int]struct{},
+	exact bool,
+) ([]SlotConfig, error) {
+	var ids []int
+	for id := range current {
+		if _, ok := desired[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	var remaining []SlotConfig
+	for _, slot := range unmatched {
+		matched := false
+		for _, id := range ids {
+			_, ok := desired[id]
+			if ok || !strings.HasPrefix(current[id].Description, string(slot.Type)) {
+				continue
+			}
+
+			candidate := slot
+			candidate.ID = id
+			slotDiff, err := a.diffSlot(ctx, candidate, current[id])
+			if err != nil {
+				return nil, err
+			}
+
+			if exact && len(slotDiff.Options) > 0 {
+				continue
+			}
+
+			desired[id] = struct{}{}
+			if len(slotDiff.Options) > 0 {
+				result.ModifySlots = append(result.ModifySlots, slotDiff)
+			}
+			matched = true
+			break
+		}
+
+		if !matched {
+			remaining = append(remaining, slot)
+		}
+	}
+	return remaining, nil
+}
+
+func (a *API) diffSlot(ctx context.Context, slot SlotConfig, info SlotInfo) (SlotDiff, error) {
+	result := SlotDiff{ID: slot.ID}
+	if !strings.HasPrefix(info.Description, string(slot.Type)) {
+		result.Type = slot.Type
+	}
+
+	if len(slot.Options) == 0 {
+		return result, nil
+	}
+
+	all, err := a.slotOptionsList(ctx, slot.ID, " -a")
+	if err != nil {
+		return SlotDiff{}, err
+	}
+
+	changed, err := a.slotOptionsList(ctx, slot.ID, "")
+	if err != nil {
+		return SlotDiff{}, err
+	}
+
+	result.Options = diffOptions(OptionChanges(slot.Options), all, changed)
+	return result, nil
+}
+
+func (a *API) slotOptionsList(
+	ctx context.Context,
+	slot int,
+	flag string,
+) (map[string]string, error) {
+	return a.optionsList(ctx, fmt.Sprintf("slot-options %d%s", slot, flag))
+}
+
+// diffOptions returns the options in desired that are different from all, sorted by name. Options
+// that are reset are compared with changed, which contains options that have non-default values.
+func diffOptions(desired OptionChanges, all, changed map[string]string) []OptionDiff {
+	var result []OptionDiff
+	for name, value := range desired {
+		current := all[name]
+		if value == nil {
+			if _, ok := changed[name]; ok {
+				result = append(result, OptionDiff{Name: name, Current: current})
+			}
+		} else if fmt.Sprintf("%v", value) != current {
+			result = append(result, OptionDiff{Name: name, Current: current, Desired: value})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Reconcile changes the client to match config, and returns the changes. Options and slots that
+// already match are not changed, so Reconcile can be run repeatedly. If dryRun is true, the
+// changes are only returned.
+func (a *API) Reconcile(config *Config, dryRun bool) (*Diff, error) {
+	return a.ReconcileContext(context.Background(), config, dryRun)
+}
+
+// ReconcileContext is Reconcile with a context.
+func (a *API) ReconcileContext(ctx context.Context, config *Config, dryRun bool) (*Diff, error) {
+	diff, err := a.DiffContext(ctx, config)
+	if err != nil || dryRun {
+		return diff, err
+	}
+
+	if len(diff.Options) > 0 {
+		if err := a.OptionsUpdateContext(ctx, optionDiffChanges(diff.Options), nil); err != nil {
+			return diff, err
+		}
+	}
+
+	for _, slot := range diff.DeleteSlots {
+		if err := a.SlotDeleteContext(ctx, slot); err != nil {
+			return diff, err
+		}
+	}
+
+	for _, slot := range diff.ModifySlots {
+		changes := SlotChanges(optionDiffChanges(slot.Options))
+		if slot.Type != "" {
+			err = a.SlotModifyContext(ctx, slot.ID, slot.Type, changes)
+		} else {
+			err = a.SlotOptionsUpdateContext(ctx, slot.ID, changes)
+		}
+
+		if err != nil {
+			return diff, err
+		}
+	}
+
+	for i, slot := range diff.AddSlots {
+		// Resets are skipped because a new slot has default options
+		changes := SlotChanges{}
+		for name, value := range slot.Options {
+			if value != nil {
+				changes[name] = value
+			}
+		}
+
+		id, err := a.SlotAddContext(ctx, slot.Type, changes)
+		if err != nil {
+			return diff, err
+		}
+		diff.AddSlots[i].ID = id
+	}
+
+	return diff, nil
+}
+
+func optionDiffChanges(diffs []OptionDiff) OptionChanges {
+	result := make(OptionChanges, len(diffs))
+	for _, diff := range diffs {
+		result[diff.Name] = diff.Desired
+	}
+	return result
+}
+
+// ExportConfig returns the options and slot options of the client that have non-default values,
+// and the slots. Options that are not in Options are not included. The config can be restored
+// with ImportConfig. Note that it contains the password and passkey if they are set.
+func (a *API) ExportConfig() (*Config, error) {
+	return a.ExportConfigContext(context.Background())
+}
+
+// ExportConfigContext is ExportConfig with a context.
+func (a *API) ExportConfigContext(ctx context.Context) (*Config, error) {
+	options, err := a.OptionsChangedContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Config{Options: OptionChanges{}, Slots: []SlotConfig{}}
+	for name, value := range options {
+		if _, ok := optionTypes[name]; ok {
+			result.Options[name] = value
+		}
+	}
+
+	slots, err := a.SlotInfoContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, slot := range slots {
+		id, err := strconv.Atoi(slot.ID)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		options, err := a.slotOptionsList(ctx, id, "")
+		if err != nil {
+			return nil, err
+		}
+
+		slotType := SlotTypeCPU
+		if strings.HasPrefix(slot.Description, string(SlotTypeGPU)) {
+			slotType = SlotTypeGPU
+		}
+
+		slotConfig := SlotConfig{ID: id, Type: slotType, Options: SlotChanges{}}
+		for name, value := range options {
+			slotConfig.Options[name] = value
+		}
+		result.Slots = append(result.Slots, slotConfig)
+	}
+
+	return result, nil
+}
+
+// ImportConfig changes the client to match a config from ExportConfig. Unlike Reconcile, options
+// and slot options that are not in config are reset to their defaults. If dryRun is true, the
+// changes are only returned.
+func (a *API) ImportConfig(config *Config, dryRun bool) (*Diff, error) {
+	return a.ImportConfigContext(context.Background(), config, dryRun)
+}
+
+// ImportConfigContext is ImportConfig with a context.
+func (a *API) ImportConfigContext(ctx context.Context, config *Config, dryRun bool) (*Diff, error) {
+	current, err := a.ExportConfigContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	full := &Config{
+		Options: resetMissing(config.Options, current.Options),
+		Slots:   make([]SlotConfig, len(config.Slots)),
+	}
+
+	currentSlots := make(map[int]SlotChanges, len(current.Slots))
+	for _, slot := range current.Slots {
+		currentSlots[slot.ID] = slot.Options
+	}
+
+	// A slot that is not in the client can take the place of any slot that is not in config, so
+	// the options of all of those slots are reset
+	others := OptionChanges{}
+	for _, slot := range current.Slots {
+		if !configHasSlot(config, slot.ID) {
+			for name := range slot.Options {
+				others[name] = nil
+			}
+		}
+	}
+
+	for i, slot := range config.Slots {
+		full.Slots[i] = slot
+		currentOptions, ok := currentSlots[slot.ID]
+		if !ok {
+			currentOptions = SlotChanges(others)
+		}
+		full.Slots[i].Options = SlotChanges(
+			resetMissing(OptionChanges(slot.Options), OptionChanges(currentOptions)),
+		)
+	}
+
+	return a.ReconcileContext(ctx, full, dryRun)
+}
+
+func configHasSlot(config *Config, id int) bool {
+	for _, slot := range config.Slots {
+		if slot.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// resetMissing returns a copy of changes with nil values for options that are in current but not
+// in changes.
+func resetMissing(changes, current OptionChanges) OptionChanges {
+	result := make(OptionChanges, len(changes))
+	for name, value := range changes {
+		result[name] = value
+	}
+
+	for name := range current {
+		if _, ok := result[name]; !ok {
+			result[name] = nil
+		}
+	}
+	return result
+}
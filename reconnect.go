@@ -0,0 +1,135 @@
+package fahapi
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"math/rand"
+	"time"
+)
+
+// ReconnectPolicy controls how a Connection is reestablished after it is lost, like when FAHClient
+// restarts.
+type ReconnectPolicy struct {
+	// MaxAttempts is the number of times to try connecting before giving up. Zero disables
+	// reconnection and a negative number means no limit.
+	MaxAttempts int
+
+	// MinBackoff is the wait after the first failed attempt. The wait doubles after each failed
+	// attempt, up to MaxBackoff if MaxBackoff is not zero.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Jitter is the fraction of each wait that is random, between 0 and 1. It keeps many
+	// connections from reconnecting at the same time.
+	Jitter float64
+}
+
+// DefaultReconnectPolicy tries to reconnect once without waiting.
+var DefaultReconnectPolicy = ReconnectPolicy{MaxAttempts: 1}
+
+// backoff returns the wait after failed attempt number attempt, starting from 1.
+func (p ReconnectPolicy) backoff(attempt int) time.Duration {
+	backoff := p.MinBackoff
+	for i := 1; i < attempt && (p.MaxBackoff == 0 || backoff < p.MaxBackoff); i++ {
+		backoff *= 2
+	}
+
+	if p.MaxBackoff != 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+
+	return backoff - time.Duration(p.Jitter*rand.Float64()*float64(backoff))
+}
+
+// WithReconnectPolicy sets the ReconnectPolicy. DefaultReconnectPolicy is used otherwise.
+func WithReconnectPolicy(policy ReconnectPolicy) DialOption {
+	return func(c *Connection) {
+		c.reconnectPolicy = policy
+	}
+}
+
+// WithOnDisconnect makes the connection call f when the connection is lost during a command. err
+// is the error from the lost connection. f is called before reconnecting, by the goroutine that
+// runs the command.
+func WithOnDisconnect(f func(err error)) DialOption {
+	return func(c *Connection) {
+		c.onDisconnect = f
+	}
+}
+
+// WithOnReconnect makes the connection call f after the connection is reestablished.
+func WithOnReconnect(f func()) DialOption {
+	return func(c *Connection) {
+		c.onReconnect = f
+	}
+}
+
+// DisconnectError is returned by Exec() when the connection was lost.
+type DisconnectError struct {
+	Err error // The error from the lost connection
+
+	// Sent is true if the command was sent before the connection was lost, in which case the
+	// client might have run it.
+	Sent bool
+
+	// Reconnected is true if the connection was reestablished. Otherwise, the next command tries
+	// to reconnect again.
+	Reconnected bool
+}
+
+func (e *DisconnectError) Error() string {
+	return fmt.Sprintf("connection lost (sent: %t, reconnected: %t): %s", e.Sent, e.Reconnected, e.Err)
+}
+
+func (e *DisconnectError) Cause() error {
+	return e.Err
+}
+
+func (e *DisconnectError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true for ErrDisconnected.
+func (e *DisconnectError) Is(target error) bool {
+	return target == ErrDisconnected
+}
+
+// Retry returns true if the command should be sent again. Commands that are safe to run twice,
+// like queries, can be retried whenever Reconnected is true.
+func (e *DisconnectError) Retry() bool {
+	return e.Reconnected && !e.Sent
+}
+
+// reconnect closes the connection and connects again following the ReconnectPolicy.
+func (c *Connection) reconnect(ctx context.Context) error {
+	c.stopReader(errors.New("reconnecting"))
+	c.Conn.Close()
+	c.interrupted = true
+
+	policy := c.reconnectPolicy
+	if policy.MaxAttempts == 0 {
+		return errors.New("reconnection is disabled")
+	}
+
+	var err error
+	for attempt := 1; policy.MaxAttempts < 0 || attempt <= policy.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-time.After(policy.backoff(attempt - 1)):
+			case <-ctx.Done():
+				return errors.WithStack(ctx.Err())
+			}
+		}
+
+		if err = c.establish(ctx); err == nil {
+			c.interrupted = false
+			if c.onReconnect != nil {
+				c.onReconnect()
+			}
+			return nil
+		}
+	}
+
+	return errors.Wrapf(err, "gave up reconnecting after %d attempts", policy.MaxAttempts)
+}
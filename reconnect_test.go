@@ -0,0 +1,113 @@
+package fahapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestReconnectPolicy_backoff(t *testing.T) {
+	tests := []struct {
+		policy   ReconnectPolicy
+		attempt  int
+		expected time.Duration
+	}{
+		{ReconnectPolicy{}, 1, 0},
+		{ReconnectPolicy{MinBackoff: time.Second}, 1, time.Second},
+		{ReconnectPolicy{MinBackoff: time.Second}, 4, time.Second * 8},
+		{ReconnectPolicy{MinBackoff: time.Second, MaxBackoff: time.Second * 5}, 4, time.Second * 5},
+		{ReconnectPolicy{MinBackoff: time.Second, MaxBackoff: time.Second * 5}, 100, time.Second * 5},
+	}
+
+	for i, test := range tests {
+		assert.Equal(t, test.expected, test.policy.backoff(test.attempt), i)
+	}
+
+	policy := ReconnectPolicy{MinBackoff: time.Second, Jitter: 0.5}
+	for i := 0; i < 10; i++ {
+		backoff := policy.backoff(1)
+		assert.True(t, backoff > time.Second/2 && backoff <= time.Second, backoff)
+	}
+}
+
+func TestConnection_ExecContext_disconnect(t *testing.T) {
+	refuse := false
+	dials := 0
+	dial := func(ctx context.Context, addr string) (net.Conn, error) {
+		dials++
+		if refuse {
+			return nil, errors.New("refused")
+		}
+
+		client, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			if _, err := server.Write([]byte("Welcome\n> ")); err != nil {
+				return
+			}
+
+			scanner := bufio.NewScanner(server)
+			for scanner.Scan() {
+				if scanner.Text() == "exit" {
+					return
+				}
+
+				if _, err := server.Write([]byte(scanner.Text() + "\n> ")); err != nil {
+					return
+				}
+			}
+		}()
+		return client, nil
+	}
+
+	var disconnectErr error
+	reconnected := 0
+	conn, err := DialConnectionContext(
+		context.Background(),
+		"",
+		WithDialFunc(dial),
+		WithReconnectPolicy(ReconnectPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}),
+		WithOnDisconnect(func(err error) { disconnectErr = err }),
+		WithOnReconnect(func() { reconnected++ }),
+	)
+	require.Nil(t, err)
+	defer conn.Close()
+
+	buffer := &bytes.Buffer{}
+	err = conn.Exec("exit", buffer)
+	var disconnect *DisconnectError
+	require.True(t, errors.As(err, &disconnect), err)
+	assert.Equal(t, io.EOF, errors.Cause(err))
+	assert.True(t, disconnect.Sent)
+	assert.True(t, disconnect.Reconnected)
+	assert.False(t, disconnect.Retry())
+	assert.Equal(t, io.EOF, errors.Cause(disconnectErr))
+	assert.Equal(t, 1, reconnected)
+
+	assert.Nil(t, conn.Exec("a", buffer))
+	assert.Equal(t, "a", buffer.String())
+
+	refuse = true
+	dials = 0
+	err = conn.Exec("exit", buffer)
+	require.True(t, errors.As(err, &disconnect), err)
+	assert.False(t, disconnect.Reconnected)
+	assert.Equal(t, 3, dials)
+
+	// The next command tries again
+	refuse = false
+	assert.Nil(t, conn.Exec("a", buffer))
+	assert.Equal(t, "a", buffer.String())
+	assert.Equal(t, 2, reconnected)
+
+	conn.Close()
+	assert.NotNil(t, conn.Exec("a", buffer))
+	assert.Equal(t, 2, reconnected)
+}
@@ -2,14 +2,18 @@ package fahapi
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/pkg/errors"
 	"log"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
+//go:generate go run ./internal/cmd/genpatch
+
 type Options struct {
 	Allow                  string     `json:"allow"`
 	CaptureDirectory       string     `json:"capture-directory"`
@@ -135,9 +139,142 @@ type Options struct {
 	WebEnable              StringBool `json:"web-enable"`
 }
 
+// OptionChanges are options for OptionsUpdate, keyed by option name. Values are turned into
+// strings using fmt.Sprintf(). A nil value resets the option to its default with "name!".
+type OptionChanges map[string]interface{}
+
+// format returns the changes sorted by name, each with a leading space. Options are set with
+// name, assign and value, and reset with "name!". Values that contain spaces are quoted.
+func (o OptionChanges) format(assign string) (string, error) {
+	names := make([]string, 0, len(o))
+	for name := range o {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		// Prevent injection attacks
+		if name == "" || strings.ContainsAny(name, "= !\t\"\\") {
+			return "", errors.Errorf("bad option name: %q", name)
+		}
+
+		b.WriteByte(' ')
+		if o[name] == nil {
+			b.WriteString(name)
+			b.WriteByte('!')
+			continue
+		}
+
+		value := fmt.Sprintf("%v", o[name])
+		if assign == " " {
+			b.WriteString(name)
+			b.WriteByte(' ')
+			b.WriteString(quoteArg(value))
+		} else {
+			b.WriteString(quoteArg(name + assign + value))
+		}
+	}
+	return b.String(), nil
+}
+
+// optionTypes maps option names to the types of the Options fields.
+var optionTypes = fieldTypes(reflect.TypeOf(Options{}))
+
+// slotOptionTypes maps slot option names to the types of the SlotOptions fields.
+var slotOptionTypes = fieldTypes(reflect.TypeOf(SlotOptions{}))
+
+// fieldTypes maps the json tags of the fields of struct type t to the field types.
+func fieldTypes(t reflect.Type) map[string]reflect.Type {
+	result := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		result[t.Field(i).Tag.Get("json")] = t.Field(i).Type
+	}
+	return result
+}
+
+// optionRanges are the valid ranges of integer options, from FAHControl.
+var optionRanges = map[string][2]int{
+	"checkpoint":           {3, 30},
+	"cpu-usage":            {0, 100},
+	"gpu-usage":            {0, 100},
+	"next-unit-percentage": {90, 100},
+}
+
+// Validate returns an error if o contains an option that is not in Options, or a value that is
+// invalid for the option.
+func (o OptionChanges) Validate() error {
+	_, err := o.validate()
+	return err
+}
+
+// validate returns an error if o contains an option that is not in Options, or a value that is
+// invalid for the option. The returned changes have values formatted like the client does.
+func (o OptionChanges) validate() (OptionChanges, error) {
+	return validateChanges(o, optionTypes, "option")
+}
+
+// validateChanges is OptionChanges.validate for options with the given types. kind is the kind
+// of option in errors.
+func validateChanges(
+	o OptionChanges,
+	types map[string]reflect.Type,
+	kind string,
+) (OptionChanges, error) {
+	result := make(OptionChanges, len(o))
+	for name, value := range o {
+		t, ok := types[name]
+		if !ok {
+			return nil, errors.Errorf("unknown %s: %q", kind, name)
+		}
+
+		if value == nil {
+			result[name] = nil
+			continue
+		}
+
+		s := fmt.Sprintf("%v", value)
+		switch t {
+		case reflect.TypeOf(StringBool(false)):
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return nil, errors.Errorf("invalid bool for %s %s: %q", kind, name, s)
+			}
+			s = strconv.FormatBool(b)
+		case reflect.TypeOf(StringInt(0)):
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return nil, errors.Errorf("invalid integer for %s %s: %q", kind, name, s)
+			}
+
+			if r, ok := optionRanges[name]; ok && (n < r[0] || n > r[1]) {
+				return nil, errors.Errorf("%s %s must be in [%d, %d]: %d", kind, name, r[0], r[1], n)
+			}
+		case reflect.TypeOf(PowerNull):
+			power, err := NewPower(s)
+			if err != nil || power == PowerNull {
+				return nil, errors.Errorf("invalid power: %q", s)
+			}
+			s = string(power)
+		}
+		result[name] = s
+	}
+	return result, nil
+}
+
+// isJSONNull returns true if b is null, which is what None is decoded as. Following the
+// encoding/json convention, UnmarshalJSON() leaves the value unchanged for null.
+func isJSONNull(b []byte) bool {
+	return bytes.Equal(b, []byte("null"))
+}
+
 type StringBool bool
 
 func (s *StringBool) UnmarshalJSON(b []byte) error {
+	if isJSONNull(b) {
+		return nil
+	}
+
 	if bytes.Equal(b, []byte(`"true"`)) {
 		*s = true
 		return nil
@@ -149,9 +286,20 @@ func (s *StringBool) UnmarshalJSON(b []byte) error {
 	return errors.Errorf("invalid StringBool: %v", b)
 }
 
+func (s StringBool) MarshalJSON() ([]byte, error) {
+	if s {
+		return []byte(`"true"`), nil
+	}
+	return []byte(`"false"`), nil
+}
+
 type StringInt int
 
 func (i *StringInt) UnmarshalJSON(b []byte) error {
+	if isJSONNull(b) {
+		return nil
+	}
+
 	var s string
 	if err := json.Unmarshal(b, &s); err != nil {
 		return err
@@ -164,6 +312,10 @@ func (i *StringInt) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+func (i StringInt) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + strconv.Itoa(int(i)) + `"`), nil
+}
+
 func (i *StringInt) FromString(s string) error {
 	integer, err := strconv.Atoi(s)
 	*i = StringInt(integer)
@@ -190,6 +342,10 @@ func NewPower(s string) (Power, error) {
 }
 
 func (p *Power) UnmarshalJSON(b []byte) error {
+	if isJSONNull(b) {
+		return nil
+	}
+
 	var s string
 	if err := json.Unmarshal(b, &s); err != nil {
 		return err
@@ -294,6 +450,10 @@ func (f FAHDuration) String() string {
 }
 
 func (f *FAHDuration) UnmarshalJSON(b []byte) error {
+	if isJSONNull(b) {
+		return nil
+	}
+
 	var s string
 	if err := json.Unmarshal(b, &s); err != nil {
 		return err
@@ -308,6 +468,29 @@ func (f *FAHDuration) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// FormatFAHDuration formats f like the client does, which ParseFAHDuration() can read back.
+func FormatFAHDuration(f FAHDuration) string {
+	if f.UnknownTime() {
+		return unknowntimeStr
+	}
+
+	d := time.Duration(f)
+	day := time.Hour * 24
+	switch {
+	case d >= day:
+		return fmt.Sprintf("%.2f days", float64(d)/float64(day))
+	case d >= time.Hour:
+		return fmt.Sprintf("%d hours %d mins", d/time.Hour, d%time.Hour/time.Minute)
+	case d >= time.Minute:
+		return fmt.Sprintf("%d mins %d secs", d/time.Minute, d%time.Minute/time.Second)
+	}
+	return fmt.Sprintf("%.2f secs", d.Seconds())
+}
+
+func (f FAHDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(FormatFAHDuration(f))
+}
+
 type SimulationInfo struct {
 	User            string  `json:"user"`
 	Team            string  `json:"team"`
@@ -356,6 +539,10 @@ func (t FAHTime) String() string {
 }
 
 func (t *FAHTime) UnmarshalJSON(b []byte) error {
+	if isJSONNull(b) {
+		return nil
+	}
+
 	var s string
 	if err := json.Unmarshal(b, &s); err != nil {
 		return err
@@ -369,6 +556,14 @@ func (t *FAHTime) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+func (t FAHTime) MarshalJSON() ([]byte, error) {
+	if t.Invalid() {
+		return json.Marshal(invalidTime)
+	}
+
+	return json.Marshal(time.Time(t).Format(time.RFC3339))
+}
+
 type SlotInfo struct {
 	ID          string                 `json:"id"`
 	Status      string                 `json:"status"`
@@ -378,9 +573,72 @@ type SlotInfo struct {
 	Idle        bool                   `json:"idle"`
 }
 
+// SlotType is the type of slot for SlotAdd and SlotModify.
+type SlotType string
+
+const (
+	SlotTypeCPU SlotType = "cpu"
+	SlotTypeGPU SlotType = "gpu"
+)
+
+// Slot option names that are used when adding slots
+const (
+	SlotCPUs        = "cpus"         // Number of CPU threads, or -1 to let the client decide
+	SlotGPUIndex    = "gpu-index"    // Index in the client's GPU list
+	SlotOpenCLIndex = "opencl-index" // OpenCL device index, or -1 to detect
+	SlotCUDAIndex   = "cuda-index"   // CUDA device index, or -1 to detect
+)
+
+// SlotChanges are options for SlotAdd, SlotModify and SlotOptionsUpdate, keyed by option name.
+// Values are turned into strings using fmt.Sprintf(). A nil value resets the option to its default
+// with "name!".
+type SlotChanges map[string]interface{}
+
+// args returns the arguments for slot-add and slot-modify, sorted by name, with a leading space.
+// Options are set with "name=value".
+func (s SlotChanges) args() (string, error) {
+	return s.format("=")
+}
+
+// Validate returns an error if s contains an option that is not in SlotOptions, or a value that is
+// invalid for the option.
+func (s SlotChanges) Validate() error {
+	_, err := s.validate()
+	return err
+}
+
+// validate is OptionChanges.validate for slot options.
+func (s SlotChanges) validate() (SlotChanges, error) {
+	result, err := validateChanges(OptionChanges(s), slotOptionTypes, "slot option")
+	return SlotChanges(result), err
+}
+
+// format is OptionChanges.format for slot options.
+func (s SlotChanges) format(assign string) (string, error) {
+	return OptionChanges(s).format(assign)
+}
+
+// SlotOptions are the options of a slot. Options that are not set on the slot have the value of
+// the global option with the same name.
 type SlotOptions struct {
-	MachineID string     `json:"machine-id"`
-	Paused    StringBool `json:"paused"`
+	Checkpoint         StringInt  `json:"checkpoint"`
+	ClientSubtype      string     `json:"client-subtype"`
+	ClientType         string     `json:"client-type"`
+	CorePriority       string     `json:"core-priority"`
+	CPUs               StringInt  `json:"cpus"`
+	CPUUsage           StringInt  `json:"cpu-usage"`
+	CUDAIndex          string     `json:"cuda-index"`
+	ExtraCoreArgs      string     `json:"extra-core-args"`
+	GPUIndex           string     `json:"gpu-index"`
+	GPUUsage           StringInt  `json:"gpu-usage"`
+	Idle               StringBool `json:"idle"`
+	MachineID          string     `json:"machine-id"`
+	MaxPacketSize      string     `json:"max-packet-size"`
+	MaxUnits           StringInt  `json:"max-units"`
+	NextUnitPercentage StringInt  `json:"next-unit-percentage"`
+	OpenCLIndex        string     `json:"opencl-index"`
+	PauseOnStart       StringBool `json:"pause-on-start"`
+	Paused             StringBool `json:"paused"`
 }
 
 type Info struct {
@@ -152,3 +152,142 @@ func TestInfo_FromSlice(t *testing.T) {
 	assert.NotEmpty(t, info.System.CPUID)
 	assert.Equal(t, info.System.CPUs, StringInt(1))
 }
+
+func TestFormatFAHDuration(t *testing.T) {
+	tests := []struct {
+		d        FAHDuration
+		expected string
+	}{
+		{0, "0.00 secs"},
+		{FAHDuration(time.Millisecond * 500), "0.50 secs"},
+		{FAHDuration(time.Minute*4 + time.Second*33), "4 mins 33 secs"},
+		{FAHDuration(time.Hour*2 + time.Minute*19 + time.Second), "2 hours 19 mins"},
+		{FAHDuration(time.Hour * 36), "1.50 days"},
+		{unknowntime, "unknowntime"},
+	}
+
+	for i, test := range tests {
+		s := FormatFAHDuration(test.d)
+		assert.Equal(t, test.expected, s, i)
+
+		parsed, err := ParseFAHDuration(s)
+		assert.Nil(t, err, i)
+		assert.Equal(t, s, FormatFAHDuration(parsed), i)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	v := struct {
+		B StringBool
+		I StringInt
+		D FAHDuration
+		T FAHTime
+		U FAHTime
+	}{
+		true,
+		1,
+		FAHDuration(time.Second),
+		FAHTime(time.Date(2020, 6, 29, 16, 2, 34, 0, time.UTC)),
+		FAHTime{},
+	}
+
+	b, err := json.Marshal(v)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		`{"B":"true","I":"1","D":"1.00 secs","T":"2020-06-29T16:02:34Z","U":"<invalid>"}`,
+		string(b),
+	)
+}
+
+func TestSlotChanges_args(t *testing.T) {
+	tests := []struct {
+		changes     SlotChanges
+		expected    string
+		expectError bool
+	}{
+		{nil, "", false},
+		{SlotChanges{SlotCPUs: 2}, " cpus=2", false},
+		{
+			SlotChanges{SlotGPUIndex: 1, SlotCUDAIndex: -1, "paused": nil},
+			" cuda-index=-1 gpu-index=1 paused!",
+			false,
+		},
+		{SlotChanges{"a=b": 1}, "", true},
+		{SlotChanges{"": 1}, "", true},
+		{SlotChanges{"a": `b "c"`}, ` "a=b \"c\""`, false},
+	}
+
+	for i, test := range tests {
+		result, err := test.changes.args()
+		assert.Equal(t, test.expected, result, i)
+		checkerror.Check(t, test.expectError, err, i)
+	}
+
+	result, err := SlotChanges{"paused": true, "cpus": nil}.format(" ")
+	assert.Nil(t, err)
+	assert.Equal(t, " cpus! paused true", result)
+
+	result, err = SlotChanges{"a": "", "b": "c d"}.format(" ")
+	assert.Nil(t, err)
+	assert.Equal(t, ` a "" b "c d"`, result)
+}
+
+func TestOptionChanges_validate(t *testing.T) {
+	tests := []struct {
+		changes     OptionChanges
+		expected    OptionChanges
+		expectError bool
+	}{
+		{OptionChanges{}, OptionChanges{}, false},
+		{
+			OptionChanges{"cpu-usage": 50, "gpu": StringBool(true), "user": "a b", "team": nil},
+			OptionChanges{"cpu-usage": "50", "gpu": "true", "user": "a b", "team": nil},
+			false,
+		},
+		{
+			OptionChanges{"power": "light", "paused": "1"},
+			OptionChanges{"power": "LIGHT", "paused": "true"},
+			false,
+		},
+		{OptionChanges{"cpu_usage": 50}, nil, true},
+		{OptionChanges{"cpu-usage": 101}, nil, true},
+		{OptionChanges{"next-unit-percentage": 50}, nil, true},
+		{OptionChanges{"team": "a"}, nil, true},
+		{OptionChanges{"paused": "a"}, nil, true},
+		{OptionChanges{"power": "max"}, nil, true},
+		{OptionChanges{"power": ""}, nil, true},
+	}
+
+	for i, test := range tests {
+		result, err := test.changes.validate()
+		assert.Equal(t, test.expected, result, i)
+		checkerror.Check(t, test.expectError, err, i)
+	}
+}
+
+func TestSlotChanges_validate(t *testing.T) {
+	tests := []struct {
+		changes     SlotChanges
+		expected    SlotChanges
+		expectError bool
+	}{
+		{SlotChanges{}, SlotChanges{}, false},
+		{
+			SlotChanges{SlotCPUs: 2, "paused": StringBool(true), "core-priority": "low", "idle": nil},
+			SlotChanges{SlotCPUs: "2", "paused": "true", "core-priority": "low", "idle": nil},
+			false,
+		},
+		{SlotChanges{"cpu_usage": 50}, nil, true},
+		{SlotChanges{"user": "a"}, nil, true},
+		{SlotChanges{"cpu-usage": 101}, nil, true},
+		{SlotChanges{SlotCPUs: "a"}, nil, true},
+		{SlotChanges{"paused": "a"}, nil, true},
+	}
+
+	for i, test := range tests {
+		result, err := test.changes.validate()
+		assert.Equal(t, test.expected, result, i)
+		checkerror.Check(t, test.expectError, err, i)
+	}
+}
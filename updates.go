@@ -0,0 +1,262 @@
+package fahapi
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"log"
+	"strings"
+	"time"
+)
+
+// Updates make the client evaluate an expression periodically and send the result without a
+// command. FAHControl uses them to refresh its views. An expression is a command prefixed with $,
+// like "$queue-info" or "$(options -a)".
+
+// UpdatesAdd makes the client evaluate expression every rate. Rate is rounded down to seconds.
+func (a *API) UpdatesAdd(id int, rate time.Duration, expression string) error {
+	return a.UpdatesAddContext(context.Background(), id, rate, expression)
+}
+
+// UpdatesAddContext is UpdatesAdd with a context.
+func (a *API) UpdatesAddContext(
+	ctx context.Context,
+	id int,
+	rate time.Duration,
+	expression string,
+) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.updatesAdd(ctx, id, rate, expression)
+}
+
+func (a *API) updatesAdd(ctx context.Context, id int, rate time.Duration, expression string) error {
+	if rate < time.Second {
+		return errors.Errorf("rate must be at least 1 second: %s", rate)
+	}
+
+	if strings.ContainsAny(expression, "\n\"") {
+		return errors.New("expression contains bad char")
+	}
+
+	command := fmt.Sprintf(`updates add %d %d "%s"`, id, rate/time.Second, expression)
+	return a.ExecContext(ctx, command, a.buffer)
+}
+
+// UpdatesDel deletes an update.
+func (a *API) UpdatesDel(id int) error {
+	return a.UpdatesDelContext(context.Background(), id)
+}
+
+// UpdatesDelContext is UpdatesDel with a context.
+func (a *API) UpdatesDelContext(ctx context.Context, id int) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.ExecContext(ctx, fmt.Sprintf("updates del %d", id), a.buffer)
+}
+
+// UpdatesList returns a listing of the updates.
+func (a *API) UpdatesList() (string, error) {
+	return a.UpdatesListContext(context.Background())
+}
+
+// UpdatesListContext is UpdatesList with a context.
+func (a *API) UpdatesListContext(ctx context.Context) (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if err := a.ExecContext(ctx, "updates list", a.buffer); err != nil {
+		return "", err
+	}
+
+	return a.buffer.String(), nil
+}
+
+// UpdatesClear deletes all updates.
+func (a *API) UpdatesClear() error {
+	return a.UpdatesClearContext(context.Background())
+}
+
+// UpdatesClearContext is UpdatesClear with a context.
+func (a *API) UpdatesClearContext(ctx context.Context) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.ExecContext(ctx, "updates clear", a.buffer)
+}
+
+// UpdatesReset makes all updates send their next result immediately.
+func (a *API) UpdatesReset() error {
+	return a.UpdatesResetContext(context.Background())
+}
+
+// UpdatesResetContext is UpdatesReset with a context.
+func (a *API) UpdatesResetContext(ctx context.Context) error {
+	a.shared.mutex.Lock()
+	a.shared.updatesResets++
+	a.shared.mutex.Unlock()
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.ExecContext(ctx, "updates reset", a.buffer)
+}
+
+// WatchQueueInfo returns a channel that receives QueueInfo() results every rate. The channel is
+// closed when ctx is done or the connection is lost.
+func (a *API) WatchQueueInfo(
+	ctx context.Context,
+	rate time.Duration,
+) (<-chan []SlotQueueInfo, error) {
+	result := make(chan []SlotQueueInfo)
+	err := a.watch(ctx, rate, "$queue-info", "units", func(message []byte) bool {
+		var info []SlotQueueInfo
+		if err := UnmarshalPyON(message, &info); err != nil {
+			log.Printf("discarded invalid queue-info update: %s", err)
+			return true
+		}
+
+		select {
+		case result <- info:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func() {
+		close(result)
+	})
+	return result, err
+}
+
+// WatchSlotInfo returns a channel that receives SlotInfo() results every rate. The channel is
+// closed when ctx is done or the connection is lost.
+func (a *API) WatchSlotInfo(ctx context.Context, rate time.Duration) (<-chan []SlotInfo, error) {
+	result := make(chan []SlotInfo)
+	err := a.watch(ctx, rate, "$slot-info", "slots", func(message []byte) bool {
+		var info []SlotInfo
+		if err := UnmarshalPyON(message, &info); err != nil {
+			log.Printf("discarded invalid slot-info update: %s", err)
+			return true
+		}
+
+		select {
+		case result <- info:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func() {
+		close(result)
+	})
+	return result, err
+}
+
+// WatchOptions returns a channel that receives OptionsGet() results every rate. The channel is
+// closed when ctx is done or the connection is lost.
+func (a *API) WatchOptions(ctx context.Context, rate time.Duration) (<-chan Options, error) {
+	result := make(chan Options)
+	err := a.watch(ctx, rate, "$(options -a)", "options", func(message []byte) bool {
+		var options Options
+		if err := UnmarshalPyON(message, &options); err != nil {
+			log.Printf("discarded invalid options update: %s", err)
+			return true
+		}
+
+		select {
+		case result <- options:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func() {
+		close(result)
+	})
+	return result, err
+}
+
+// rateTolerance is how much earlier than its rate a watcher accepts a message, because messages
+// from its own update can arrive a little early.
+const rateTolerance = time.Millisecond * 500
+
+// watch adds an update for expression and calls handle for each message named name until handle
+// returns false, ctx is done or the connection is lost. Then the update is deleted and done is
+// called. If an error is returned, done is not called.
+//
+// Messages of other watchers of the same kind cannot be told apart from the messages of this
+// watcher, so messages that arrive sooner than rate after the last one are skipped, unless
+// UpdatesReset() was called.
+func (a *API) watch(
+	ctx context.Context,
+	rate time.Duration,
+	expression string,
+	name string,
+	handle func(message []byte) bool,
+	done func(),
+) error {
+	s := a.subscribe(name)
+
+	a.shared.mutex.Lock()
+	id := a.shared.nextUpdateID
+	a.shared.nextUpdateID++
+	resets := a.shared.updatesResets
+	a.shared.mutex.Unlock()
+
+	a.mutex.Lock()
+	err := a.updatesAdd(ctx, id, rate, expression)
+	a.mutex.Unlock()
+
+	if err != nil {
+		a.unsubscribe(s)
+		return err
+	}
+
+	rate = rate / time.Second * time.Second // Like the client
+	go func() {
+		defer done()
+		defer a.unsubscribe(s)
+
+		var last time.Time
+		for {
+			message, err := s.next(ctx)
+			if err != nil {
+				break
+			}
+
+			// The first message is the result of the update, and the reset might be for the next
+			now := time.Now()
+			if !last.IsZero() {
+				current := a.updatesResets()
+				if now.Sub(last) < rate-rateTolerance && current == resets {
+					continue
+				}
+				resets = current
+			}
+			last = now
+
+			if !handle(message) {
+				break
+			}
+		}
+
+		if a.isClosed() {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		if err := a.UpdatesDelContext(ctx, id); err != nil {
+			log.Printf("failed to delete update %d: %s", id, err)
+		}
+	}()
+	return nil
+}
+
+func (a *API) updatesResets() int {
+	a.shared.mutex.Lock()
+	defer a.shared.mutex.Unlock()
+
+	return a.shared.updatesResets
+}
@@ -0,0 +1,80 @@
+package fahapi
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestAPI_UpdatesAdd(t *testing.T) {
+	listener := serveFake(t, func(command string) string {
+		return command + "\n> "
+	})
+	defer listener.Close()
+
+	api, err := Dial(listener.Addr().(*net.TCPAddr))
+	require.Nil(t, err)
+	defer api.Close()
+
+	tests := []struct {
+		rate        time.Duration
+		expression  string
+		expectError bool
+	}{
+		{time.Second, "$queue-info", false},
+		{time.Millisecond, "$queue-info", true},
+		{time.Second, "$\"", true},
+		{time.Second, "$\n", true},
+	}
+
+	for i, test := range tests {
+		assert.Equal(t, test.expectError, api.UpdatesAdd(0, test.rate, test.expression) != nil, i)
+	}
+}
+
+func TestAPI_WatchQueueInfo(t *testing.T) {
+	deleted := make(chan string, 1)
+	listener := serveFake(t, func(command string) string {
+		switch command {
+		case `updates add 0 1 "$queue-info"`:
+			return "\n> \nPyON 1 units\n[{\"id\": \"01\"}]\n---\n"
+		case "queue-info":
+			return "\nPyON 1 units\n[{\"id\": \"02\"}]\n---\n> "
+		case "updates del 0":
+			deleted <- command
+		}
+		return "\n> "
+	})
+	defer listener.Close()
+
+	api, err := Dial(listener.Addr().(*net.TCPAddr))
+	require.Nil(t, err)
+	defer api.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	units, err := api.WatchQueueInfo(ctx, time.Second)
+	require.Nil(t, err)
+
+	// The response to queue-info has the same name as the update
+	info, err := api.QueueInfo()
+	require.Nil(t, err)
+	require.Len(t, info, 1)
+	assert.Equal(t, "02", info[0].ID)
+
+	update := <-units
+	require.Len(t, update, 1)
+	assert.Equal(t, "01", update[0].ID)
+
+	cancel()
+	_, ok := <-units
+	assert.False(t, ok)
+
+	select {
+	case <-deleted:
+	case <-time.After(time.Second):
+		t.Error("update was not deleted")
+	}
+}
@@ -0,0 +1,224 @@
+package fahapi
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"strconv"
+	"time"
+)
+
+// verifyInterval is the time between slot-info commands while a verified command waits for the
+// slot to change.
+const verifyInterval = 100 * time.Millisecond
+
+// VerifyError is returned by the Verified methods if the slot did not reach the expected state
+// before the timeout.
+type VerifyError struct {
+	Command  string
+	Expected string   // Description of the expected state
+	Slot     SlotInfo // Last state of the slot
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf(
+		"%s: slot %s has status %s (reason %q, idle %t); expected %s",
+		e.Command,
+		e.Slot.ID,
+		e.Slot.Status,
+		e.Slot.Reason,
+		e.Slot.Idle,
+		e.Expected,
+	)
+}
+
+// slotCheck is a post-condition of a command. found is false if the slot does not exist.
+type slotCheck struct {
+	expected string
+	done     func(info SlotInfo, found bool) bool
+}
+
+var (
+	pausedCheck = slotCheck{"status PAUSED", func(info SlotInfo, found bool) bool {
+		return found && info.Status == "PAUSED"
+	}}
+	// The slot may stay paused for other reasons, such as waiting for idle
+	unpausedCheck = slotCheck{"not paused by user", func(info SlotInfo, found bool) bool {
+		return found && (info.Status != "PAUSED" || !pausedByUser(info.Reason))
+	}}
+	// A slot without a work unit pauses as soon as it is finished
+	finishingCheck = slotCheck{"status FINISHING or PAUSED", func(info SlotInfo, found bool) bool {
+		return found && (info.Status == "FINISHING" || info.Status == "PAUSED")
+	}}
+	onIdleCheck = slotCheck{"idle true", func(info SlotInfo, found bool) bool {
+		return found && info.Idle
+	}}
+	alwaysOnCheck = slotCheck{"idle false", func(info SlotInfo, found bool) bool {
+		return found && !info.Idle
+	}}
+	deletedCheck = slotCheck{"slot to be deleted", func(info SlotInfo, found bool) bool {
+		return !found
+	}}
+)
+
+func pausedByUser(reason string) bool {
+	return reason == "" || reason == "paused" || reason == "by user"
+}
+
+// PauseSlotVerified is PauseSlot, and waits up to timeout for the status of the slot to be PAUSED.
+// If the slot does not exist, an error that matches ErrInvalidSlot is returned before the command
+// is sent. If the slot does not change in time, a *VerifyError is returned.
+func (a *API) PauseSlotVerified(slot int, timeout time.Duration) error {
+	return a.PauseSlotVerifiedContext(context.Background(), slot, timeout)
+}
+
+// PauseSlotVerifiedContext is PauseSlotVerified with a context.
+func (a *API) PauseSlotVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	return a.verifySlot(ctx, slot, timeout, fmt.Sprintf("pause %d", slot), pausedCheck)
+}
+
+// UnpauseSlotVerified is UnpauseSlot, and waits up to timeout until the slot is not paused by the
+// user. The slot can still be paused for another reason. See PauseSlotVerified for the errors.
+func (a *API) UnpauseSlotVerified(slot int, timeout time.Duration) error {
+	return a.UnpauseSlotVerifiedContext(context.Background(), slot, timeout)
+}
+
+// UnpauseSlotVerifiedContext is UnpauseSlotVerified with a context.
+func (a *API) UnpauseSlotVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	return a.verifySlot(ctx, slot, timeout, fmt.Sprintf("unpause %d", slot), unpausedCheck)
+}
+
+// FinishSlotVerified is FinishSlot, and waits up to timeout for the status of the slot to be
+// FINISHING or PAUSED. See PauseSlotVerified for the errors.
+func (a *API) FinishSlotVerified(slot int, timeout time.Duration) error {
+	return a.FinishSlotVerifiedContext(context.Background(), slot, timeout)
+}
+
+// FinishSlotVerifiedContext is FinishSlotVerified with a context.
+func (a *API) FinishSlotVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	return a.verifySlot(ctx, slot, timeout, fmt.Sprintf("finish %d", slot), finishingCheck)
+}
+
+// OnIdleVerified is OnIdle, and waits up to timeout for SlotInfo.Idle to be true. See
+// PauseSlotVerified for the errors.
+func (a *API) OnIdleVerified(slot int, timeout time.Duration) error {
+	return a.OnIdleVerifiedContext(context.Background(), slot, timeout)
+}
+
+// OnIdleVerifiedContext is OnIdleVerified with a context.
+func (a *API) OnIdleVerifiedContext(ctx context.Context, slot int, timeout time.Duration) error {
+	return a.verifySlot(ctx, slot, timeout, fmt.Sprintf("on_idle %d", slot), onIdleCheck)
+}
+
+// AlwaysOnVerified is AlwaysOn, and waits up to timeout for SlotInfo.Idle to be false. See
+// PauseSlotVerified for the errors.
+func (a *API) AlwaysOnVerified(slot int, timeout time.Duration) error {
+	return a.AlwaysOnVerifiedContext(context.Background(), slot, timeout)
+}
+
+// AlwaysOnVerifiedContext is AlwaysOnVerified with a context.
+func (a *API) AlwaysOnVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	return a.verifySlot(ctx, slot, timeout, fmt.Sprintf("always_on %d", slot), alwaysOnCheck)
+}
+
+// SlotDeleteVerified is SlotDelete, and waits up to timeout for the slot to be removed from
+// SlotInfo. See PauseSlotVerified for the errors.
+func (a *API) SlotDeleteVerified(slot int, timeout time.Duration) error {
+	return a.SlotDeleteVerifiedContext(context.Background(), slot, timeout)
+}
+
+// SlotDeleteVerifiedContext is SlotDeleteVerified with a context.
+func (a *API) SlotDeleteVerifiedContext(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+) error {
+	return a.verifySlot(ctx, slot, timeout, fmt.Sprintf("slot-delete %d", slot), deletedCheck)
+}
+
+// verifySlot checks that slot exists, sends command, and polls SlotInfo until check is done or
+// timeout passes.
+func (a *API) verifySlot(
+	ctx context.Context,
+	slot int,
+	timeout time.Duration,
+	command string,
+	check slotCheck,
+) error {
+	if _, found, err := a.findSlot(ctx, slot); err != nil {
+		return err
+	} else if !found {
+		return errors.Wrapf(ErrInvalidSlot, "%s: slot %d does not exist", command, slot)
+	}
+
+	if err := a.execLocked(ctx, command); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		info, found, err := a.findSlot(ctx, slot)
+		if err != nil {
+			return err
+		}
+
+		if check.done(info, found) {
+			return nil
+		}
+
+		if !time.Now().Before(deadline) {
+			return errors.WithStack(&VerifyError{
+				Command:  command,
+				Expected: check.expected,
+				Slot:     info,
+			})
+		}
+
+		timer := time.NewTimer(verifyInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.WithStack(ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// execLocked executes a command whose response is not used.
+func (a *API) execLocked(ctx context.Context, command string) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.ExecContext(ctx, command, a.buffer)
+}
+
+// findSlot returns the info of slot. found is false if there is no such slot.
+func (a *API) findSlot(ctx context.Context, slot int) (info SlotInfo, found bool, err error) {
+	slots, err := a.SlotInfoContext(ctx)
+	if err != nil {
+		return SlotInfo{}, false, err
+	}
+
+	for _, info := range slots {
+		if id, err := strconv.Atoi(info.ID); err == nil && id == slot {
+			return info, true, nil
+		}
+	}
+	return SlotInfo{}, false, nil
+}
@@ -0,0 +1,46 @@
+package fahapi
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSlotCheck(t *testing.T) {
+	tests := []struct {
+		check    slotCheck
+		info     SlotInfo
+		found    bool
+		expected bool
+	}{
+		{pausedCheck, SlotInfo{Status: "PAUSED"}, true, true},
+		{pausedCheck, SlotInfo{Status: "RUNNING"}, true, false},
+		{pausedCheck, SlotInfo{}, false, false},
+		{unpausedCheck, SlotInfo{Status: "RUNNING"}, true, true},
+		{unpausedCheck, SlotInfo{Status: "PAUSED", Reason: "by user"}, true, false},
+		{unpausedCheck, SlotInfo{Status: "PAUSED", Reason: "waiting for idle"}, true, true},
+		{finishingCheck, SlotInfo{Status: "FINISHING"}, true, true},
+		{finishingCheck, SlotInfo{Status: "PAUSED"}, true, true},
+		{finishingCheck, SlotInfo{Status: "RUNNING"}, true, false},
+		{onIdleCheck, SlotInfo{Idle: true}, true, true},
+		{alwaysOnCheck, SlotInfo{Idle: true}, true, false},
+		{deletedCheck, SlotInfo{}, false, true},
+		{deletedCheck, SlotInfo{}, true, false},
+	}
+
+	for i, test := range tests {
+		assert.Equal(t, test.expected, test.check.done(test.info, test.found), i)
+	}
+}
+
+func TestVerifyError_Error(t *testing.T) {
+	err := &VerifyError{
+		Command:  "pause 0",
+		Expected: "status PAUSED",
+		Slot:     SlotInfo{ID: "00", Status: "RUNNING"},
+	}
+	assert.Equal(
+		t,
+		`pause 0: slot 00 has status RUNNING (reason "", idle false); expected status PAUSED`,
+		err.Error(),
+	)
+}
@@ -0,0 +1,294 @@
+package fahapi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// WatchEventType is the type of a WatchEvent.
+type WatchEventType int
+
+const (
+	EventWUAssigned      WatchEventType = iota // A unit was added to the queue
+	EventWUDownloading                         // State changed to DOWNLOAD
+	EventWURunning                             // State changed to RUNNING
+	EventWUProgress                            // PercentDone changed
+	EventWUReadyToUpload                       // State changed to SEND
+	EventWUUploaded                            // A SEND unit left the queue or was credited
+	EventWUCredited                            // The credit of an uploaded unit was logged
+	EventWUFailed                              // Error changed, or a unit left the queue early
+	EventSlotPaused                            // Status changed to PAUSED
+	EventSlotUnpaused                          // Status changed from PAUSED
+	EventSlotFinishing                         // Status changed to FINISHING
+)
+
+var watchEventTypeNames = [...]string{
+	"WUAssigned",
+	"WUDownloading",
+	"WURunning",
+	"WUProgress",
+	"WUReadyToUpload",
+	"WUUploaded",
+	"WUCredited",
+	"WUFailed",
+	"SlotPaused",
+	"SlotUnpaused",
+	"SlotFinishing",
+}
+
+func (t WatchEventType) String() string {
+	if t < 0 || int(t) >= len(watchEventTypeNames) {
+		return "WatchEventType(" + strconv.Itoa(int(t)) + ")"
+	}
+	return watchEventTypeNames[t]
+}
+
+// WatchEvent is a change in the queue or in a slot.
+type WatchEvent struct {
+	Type WatchEventType
+	// Unit is set for WU events. If the unit left the queue, it is the last snapshot of the unit.
+	Unit SlotQueueInfo
+	Slot SlotInfo // Set for slot events
+	// Credit is the final credit estimate that the client logged when the work server accepted
+	// the unit. Set for EventWUCredited.
+	Credit float64
+}
+
+// unitKey identifies a unit. The queue ID is reused for later units.
+type unitKey struct {
+	id                       string
+	project, run, clone, gen int
+}
+
+func keyOf(unit SlotQueueInfo) unitKey {
+	return unitKey{unit.ID, unit.Project, unit.Run, unit.Clone, unit.Gen}
+}
+
+// Watcher turns successive snapshots of QueueInfo() and SlotInfo() into events. The first snapshot
+// of each kind only sets the initial state and returns no events. The queue does not show if a
+// unit was credited, so credit events come from log lines that are passed to Log(). The zero
+// value is ready to use.
+type Watcher struct {
+	units     []SlotQueueInfo
+	unitsSeen bool
+	slots     []SlotInfo
+	slotsSeen bool
+
+	uploaded map[string]SlotQueueInfo // Units that left the queue from SEND, by queue ID
+	credited map[unitKey]struct{}     // Units in the queue whose credit was logged
+}
+
+// Units returns the events between the previous snapshot of the queue and units.
+func (w *Watcher) Units(units []SlotQueueInfo) []WatchEvent {
+	previous := make(map[unitKey]SlotQueueInfo, len(w.units))
+	for _, unit := range w.units {
+		previous[keyOf(unit)] = unit
+	}
+
+	var result []WatchEvent
+	if w.unitsSeen {
+		current := make(map[unitKey]struct{}, len(units))
+		for _, unit := range units {
+			current[keyOf(unit)] = struct{}{}
+			result = append(result, unitEvents(previous[keyOf(unit)], unit)...)
+		}
+
+		for _, unit := range w.units {
+			if _, ok := current[keyOf(unit)]; ok {
+				continue
+			}
+
+			if _, ok := w.credited[keyOf(unit)]; ok {
+				// The events were returned by Log()
+				delete(w.credited, keyOf(unit))
+			} else if unit.State == "SEND" {
+				result = append(result, WatchEvent{Type: EventWUUploaded, Unit: unit})
+				if w.uploaded == nil {
+					w.uploaded = map[string]SlotQueueInfo{}
+				}
+				w.uploaded[unit.ID] = unit
+			} else if unit.Error == "" || unit.Error == "NO_ERROR" {
+				result = append(result, WatchEvent{Type: EventWUFailed, Unit: unit})
+			}
+		}
+	}
+
+	w.units = append([]SlotQueueInfo(nil), units...)
+	w.unitsSeen = true
+	return result
+}
+
+// Log returns EventWUCredited if record is the credit line of a unit in the SEND state, or of a
+// unit that left the queue from SEND. If the unit is still in the queue, EventWUUploaded is
+// returned first. Only pass lines that were logged after the first snapshot of the queue, because
+// queue IDs are reused.
+func (w *Watcher) Log(record LogRecord) []WatchEvent {
+	if record.Event != LogEventCredit || record.WU < 0 {
+		return nil
+	}
+
+	id := fmt.Sprintf("%02d", record.WU)
+	for _, unit := range w.units {
+		if unit.ID != id || unit.State != "SEND" {
+			continue
+		}
+
+		if _, ok := w.credited[keyOf(unit)]; ok {
+			return nil
+		}
+
+		if w.credited == nil {
+			w.credited = map[unitKey]struct{}{}
+		}
+		w.credited[keyOf(unit)] = struct{}{}
+		return []WatchEvent{
+			{Type: EventWUUploaded, Unit: unit},
+			{Type: EventWUCredited, Unit: unit, Credit: record.Credit},
+		}
+	}
+
+	if unit, ok := w.uploaded[id]; ok {
+		delete(w.uploaded, id)
+		return []WatchEvent{{Type: EventWUCredited, Unit: unit, Credit: record.Credit}}
+	}
+	return nil
+}
+
+// unitEvents returns the events between two snapshots of a unit. previous is the zero value for a
+// new unit.
+func unitEvents(previous, unit SlotQueueInfo) []WatchEvent {
+	var result []WatchEvent
+	add := func(t WatchEventType) {
+		result = append(result, WatchEvent{Type: t, Unit: unit})
+	}
+
+	if previous.ID == "" {
+		add(EventWUAssigned)
+	}
+
+	if unit.State != previous.State {
+		switch unit.State {
+		case "DOWNLOAD":
+			add(EventWUDownloading)
+		case "RUNNING":
+			add(EventWURunning)
+		case "SEND":
+			add(EventWUReadyToUpload)
+		}
+	}
+
+	if previous.ID != "" && unit.PercentDone != previous.PercentDone {
+		add(EventWUProgress)
+	}
+
+	if unit.Error != previous.Error && unit.Error != "" && unit.Error != "NO_ERROR" {
+		add(EventWUFailed)
+	}
+	return result
+}
+
+// Slots returns the events between the previous snapshot of the slots and slots.
+func (w *Watcher) Slots(slots []SlotInfo) []WatchEvent {
+	previous := make(map[string]SlotInfo, len(w.slots))
+	for _, slot := range w.slots {
+		previous[slot.ID] = slot
+	}
+
+	var result []WatchEvent
+	if w.slotsSeen {
+		for _, slot := range slots {
+			old, ok := previous[slot.ID]
+			if !ok || old.Status == slot.Status {
+				continue
+			}
+
+			if slot.Status == "PAUSED" {
+				result = append(result, WatchEvent{Type: EventSlotPaused, Slot: slot})
+			} else if old.Status == "PAUSED" {
+				result = append(result, WatchEvent{Type: EventSlotUnpaused, Slot: slot})
+			}
+
+			if slot.Status == "FINISHING" {
+				result = append(result, WatchEvent{Type: EventSlotFinishing, Slot: slot})
+			}
+		}
+	}
+
+	w.slots = append([]SlotInfo(nil), slots...)
+	w.slotsSeen = true
+	return result
+}
+
+// WatchEvents returns a channel that receives events from WatchQueueInfo() and WatchSlotInfo()
+// every rate, and from new log lines, using a Watcher. Log updates are enabled like SubscribeLog()
+// for credit events. The channel is closed when ctx is done or the connection is lost.
+func (a *API) WatchEvents(ctx context.Context, rate time.Duration) (<-chan WatchEvent, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	units, err := a.WatchQueueInfo(ctx, rate)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	slots, err := a.WatchSlotInfo(ctx, rate)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	lines, err := a.subscribeLog(ctx, true)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	result := make(chan WatchEvent)
+	go func() {
+		defer close(result)
+		defer cancel()
+
+		watcher := &Watcher{}
+		// All channels are drained after one is closed so that their goroutines can end
+		for units != nil || slots != nil || lines != nil {
+			var events []WatchEvent
+			select {
+			case info, ok := <-units:
+				if !ok {
+					units = nil
+					cancel()
+					continue
+				}
+				events = watcher.Units(info)
+			case info, ok := <-slots:
+				if !ok {
+					slots = nil
+					cancel()
+					continue
+				}
+				events = watcher.Slots(info)
+			case line, ok := <-lines:
+				if !ok {
+					lines = nil
+					cancel()
+					continue
+				}
+
+				if record, ok := ParseLogLine(line); ok {
+					events = watcher.Log(record)
+				}
+			}
+
+			for _, event := range events {
+				select {
+				case result <- event:
+				case <-ctx.Done():
+				}
+			}
+		}
+	}()
+
+	return result, nil
+}
@@ -0,0 +1,149 @@
+package fahapi
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWatcher_Units(t *testing.T) {
+	unit := func(id string, gen int, state string, percent string, err string) SlotQueueInfo {
+		return SlotQueueInfo{
+			ID:          id,
+			Project:     16435,
+			Gen:         gen,
+			State:       state,
+			PercentDone: percent,
+			Error:       err,
+		}
+	}
+
+	download := unit("01", 1, "DOWNLOAD", "0.00%", "NO_ERROR")
+	running := unit("01", 1, "RUNNING", "0.00%", "NO_ERROR")
+	progress := unit("01", 1, "RUNNING", "50.00%", "NO_ERROR")
+	send := unit("01", 1, "SEND", "100.00%", "NO_ERROR")
+	next := unit("01", 2, "RUNNING", "0.00%", "NO_ERROR")
+	faulty := unit("01", 2, "RUNNING", "0.00%", "FAULTY")
+	dumped := unit("00", 3, "RUNNING", "1.00%", "NO_ERROR")
+
+	tests := []struct {
+		units    []SlotQueueInfo
+		expected []WatchEvent
+	}{
+		{[]SlotQueueInfo{dumped}, nil},
+		{
+			[]SlotQueueInfo{dumped, download},
+			[]WatchEvent{
+				{Type: EventWUAssigned, Unit: download},
+				{Type: EventWUDownloading, Unit: download},
+			},
+		},
+		{[]SlotQueueInfo{dumped, running}, []WatchEvent{{Type: EventWURunning, Unit: running}}},
+		{[]SlotQueueInfo{dumped, progress}, []WatchEvent{{Type: EventWUProgress, Unit: progress}}},
+		{[]SlotQueueInfo{dumped, progress}, nil},
+		{
+			[]SlotQueueInfo{dumped, send},
+			[]WatchEvent{
+				{Type: EventWUReadyToUpload, Unit: send},
+				{Type: EventWUProgress, Unit: send},
+			},
+		},
+		{
+			[]SlotQueueInfo{next},
+			[]WatchEvent{
+				{Type: EventWUAssigned, Unit: next},
+				{Type: EventWURunning, Unit: next},
+				{Type: EventWUFailed, Unit: dumped},
+				{Type: EventWUUploaded, Unit: send},
+			},
+		},
+		{[]SlotQueueInfo{faulty}, []WatchEvent{{Type: EventWUFailed, Unit: faulty}}},
+		{[]SlotQueueInfo{}, nil},
+	}
+
+	watcher := Watcher{}
+	for i, test := range tests {
+		assert.Equal(t, test.expected, watcher.Units(test.units), i)
+	}
+}
+
+func TestWatcher_Log(t *testing.T) {
+	credit := func(wu int) LogRecord {
+		return LogRecord{WU: wu, Slot: 0, Event: LogEventCredit, Credit: 9405}
+	}
+
+	send := SlotQueueInfo{ID: "01", Gen: 1, State: "SEND"}
+	next := SlotQueueInfo{ID: "01", Gen: 2, State: "RUNNING"}
+	other := SlotQueueInfo{ID: "02", Gen: 1, State: "SEND"}
+
+	watcher := Watcher{}
+	assert.Empty(t, watcher.Log(credit(1))) // No snapshot
+	assert.Empty(t, watcher.Units([]SlotQueueInfo{send, other}))
+
+	// Credited while in the queue
+	assert.Empty(t, watcher.Log(LogRecord{WU: 1, Event: LogEventUpload}))
+	assert.Equal(
+		t,
+		[]WatchEvent{
+			{Type: EventWUUploaded, Unit: send},
+			{Type: EventWUCredited, Unit: send, Credit: 9405},
+		},
+		watcher.Log(credit(1)),
+	)
+	assert.Empty(t, watcher.Log(credit(1)))
+	assert.Equal(
+		t,
+		[]WatchEvent{{Type: EventWUAssigned, Unit: next}, {Type: EventWURunning, Unit: next}},
+		watcher.Units([]SlotQueueInfo{next, other}),
+	)
+	assert.Empty(t, watcher.Log(credit(1))) // Not uploaded
+
+	// Credited after leaving the queue
+	assert.Equal(
+		t,
+		[]WatchEvent{{Type: EventWUUploaded, Unit: other}},
+		watcher.Units([]SlotQueueInfo{next}),
+	)
+	assert.Equal(
+		t,
+		[]WatchEvent{{Type: EventWUCredited, Unit: other, Credit: 9405}},
+		watcher.Log(credit(2)),
+	)
+	assert.Empty(t, watcher.Log(credit(2)))
+	assert.Empty(t, watcher.Log(credit(-1)))
+}
+
+func TestWatcher_Slots(t *testing.T) {
+	slot := func(id string, status string) SlotInfo {
+		return SlotInfo{ID: id, Status: status}
+	}
+
+	tests := []struct {
+		slots    []SlotInfo
+		expected []WatchEvent
+	}{
+		{[]SlotInfo{slot("00", "PAUSED")}, nil},
+		{[]SlotInfo{slot("00", "PAUSED"), slot("01", "RUNNING")}, nil},
+		{
+			[]SlotInfo{slot("00", "RUNNING"), slot("01", "PAUSED")},
+			[]WatchEvent{
+				{Type: EventSlotUnpaused, Slot: slot("00", "RUNNING")},
+				{Type: EventSlotPaused, Slot: slot("01", "PAUSED")},
+			},
+		},
+		{
+			[]SlotInfo{slot("00", "FINISHING"), slot("01", "FINISHING")},
+			[]WatchEvent{
+				{Type: EventSlotFinishing, Slot: slot("00", "FINISHING")},
+				{Type: EventSlotUnpaused, Slot: slot("01", "FINISHING")},
+				{Type: EventSlotFinishing, Slot: slot("01", "FINISHING")},
+			},
+		},
+	}
+
+	watcher := Watcher{}
+	for i, test := range tests {
+		assert.Equal(t, test.expected, watcher.Slots(test.slots), i)
+	}
+
+	assert.Equal(t, "SlotPaused", EventSlotPaused.String())
+}